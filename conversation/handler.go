@@ -2,11 +2,13 @@ package conversation // import "heytobi.dev/fuse/conversation"
 
 import (
 	"context"
+	"sync"
+
 	"heytobi.dev/fuse/telegram"
 )
 
 type bot interface {
-	SendMessage(message *telegram.SendMessageRequest) (*telegram.ActionResult, error)
+	SendMessage(ctx context.Context, message *telegram.SendMessageRequest) (*telegram.Message, error)
 }
 
 // Handler is a suggested default handler. It acts as an orchestrator of the non-command messages received
@@ -16,8 +18,13 @@ type bot interface {
 // their own state management.
 //
 // If it doesn't work well with your use case, you can implement & register a custom one as your default handler.
+//
+// Handler is safe for concurrent use, so it can be used with a bot that processes updates with multiple workers. Since
+// the bot processes updates from the same chat in order, sequences receive a chat's messages in the order they were
+// sent.
 type Handler struct {
 	bot             bot
+	mu              sync.RWMutex
 	activeSequences map[int64]Sequence
 	defaultSequence Sequence
 }
@@ -34,7 +41,11 @@ func NewHandler(bot bot) *Handler {
 func (h *Handler) Handle(ctx context.Context, update *telegram.Update) error {
 	if update != nil && update.Message != nil {
 		// check if there is an active sequence for this user, delegate to that sequence if there is one.
-		if sequence, hasActiveSequence := h.activeSequences[update.Message.Chat.ID]; hasActiveSequence {
+		h.mu.RLock()
+		sequence, hasActiveSequence := h.activeSequences[update.Message.Chat.ID]
+		h.mu.RUnlock()
+
+		if hasActiveSequence {
 			err := sequence.Process(ctx, update)
 			if err != nil {
 				return err
@@ -56,6 +67,9 @@ func (h *Handler) Handle(ctx context.Context, update *telegram.Update) error {
 // RegisterActiveSequence registers the active sequence for the given user. New registrations always override any already
 // registered sequence. There can be at most 1 active sequences for a user, tracked by the telegram chat ID.
 func (h *Handler) RegisterActiveSequence(chatID int64, sequence Sequence) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
 	h.activeSequences[chatID] = sequence
 	return nil
 }
@@ -63,6 +77,9 @@ func (h *Handler) RegisterActiveSequence(chatID int64, sequence Sequence) error
 // DeregisterActiveSequence deletes the active sequence for a user. Sequences can call this method once their flow has
 // been completed.
 func (h *Handler) DeregisterActiveSequence(chatID int64) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
 	delete(h.activeSequences, chatID)
 	return nil
 }
@@ -6,6 +6,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"heytobi.dev/fuse/telegram"
+	"sync"
 	"testing"
 )
 
@@ -84,3 +85,27 @@ func TestHandleReturnsErrorIfProcessFails(t *testing.T) {
 	assert.Error(t, err)
 	assert.Equal(t, expectedError, err)
 }
+
+func TestHandleIsSafeForConcurrentUse(t *testing.T) {
+	sequence := &mockSequence{}
+	sequence.On("Process", mock.Anything, mock.Anything).Return(nil)
+
+	handler := NewHandler(&mockBot{})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		chatID := int64(i)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_ = handler.RegisterActiveSequence(chatID, sequence)
+			_ = handler.Handle(context.Background(), &telegram.Update{
+				Message: &telegram.Message{Chat: &telegram.Chat{ID: chatID}},
+			})
+			_ = handler.DeregisterActiveSequence(chatID)
+		}()
+	}
+	wg.Wait()
+
+	assert.Empty(t, handler.activeSequences)
+}
@@ -34,12 +34,15 @@ func (m *mockSequence) GetName() string {
 	return args.String(0)
 }
 
-func (m *mockBot) SendMessage(message *telegram.SendMessageRequest) (*telegram.ActionResult, error) {
-	args := m.Called(message)
+func (m *mockBot) SendMessage(
+	ctx context.Context,
+	message *telegram.SendMessageRequest,
+) (*telegram.Message, error) {
+	args := m.Called(ctx, message)
 
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 
-	return args.Get(0).(*telegram.ActionResult), args.Error(1)
+	return args.Get(0).(*telegram.Message), args.Error(1)
 }
@@ -1,10 +1,17 @@
 package job
 
-import "github.com/stretchr/testify/mock"
+import (
+	"context"
+	"sync/atomic"
+
+	"github.com/stretchr/testify/mock"
+)
 
 type mockJob struct {
 	mock.Mock
+	runs atomic.Int32
 }
 
-func (m *mockJob) Run() {
+func (m *mockJob) Run(ctx context.Context) {
+	m.runs.Add(1)
 }
@@ -1,7 +1,10 @@
 package job // import "heytobi.dev/fuse/job"
 import (
-	"github.com/pkg/errors"
+	"context"
+	"sync"
 	"time"
+
+	"github.com/pkg/errors"
 )
 
 var (
@@ -11,7 +14,7 @@ var (
 
 // Job defines a schedule-able job
 type job interface {
-	Run()
+	Run(ctx context.Context)
 }
 
 // ScheduledJob defines a job that is run every n milliseconds, n being defined in intervalMS. The job is executed the
@@ -19,6 +22,9 @@ type job interface {
 type ScheduledJob struct {
 	job        job
 	intervalMS int64
+	mu         sync.Mutex
+	cancel     context.CancelFunc
+	done       chan struct{}
 }
 
 func NewScheduledJob(job job, intervalMS int64) (*ScheduledJob, error) {
@@ -36,11 +42,51 @@ func NewScheduledJob(job job, intervalMS int64) (*ScheduledJob, error) {
 	}, nil
 }
 
+// Start runs the job in the background until Stop is called.
 func (j *ScheduledJob) Start() {
+	j.StartWithContext(context.Background())
+}
+
+// StartWithContext runs the job in the background until the given context is cancelled or Stop is called.
+// The context passed to the job is cancelled when the job is stopped.
+func (j *ScheduledJob) StartWithContext(ctx context.Context) {
+	ctx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+
+	j.mu.Lock()
+	j.cancel = cancel
+	j.done = done
+	j.mu.Unlock()
+
 	go func() {
+		defer close(done)
+
+		timer := time.NewTimer(0)
+		defer timer.Stop()
+
 		for {
-			j.job.Run()
-			time.Sleep(time.Duration(j.intervalMS) * time.Millisecond)
+			select {
+			case <-ctx.Done():
+				return
+			case <-timer.C:
+			}
+
+			j.job.Run(ctx)
+			timer.Reset(time.Duration(j.intervalMS) * time.Millisecond)
 		}
 	}()
 }
+
+// Stop stops the job from being scheduled again and waits for the current run, if any, to complete.
+func (j *ScheduledJob) Stop() {
+	j.mu.Lock()
+	cancel, done := j.cancel, j.done
+	j.mu.Unlock()
+
+	if cancel == nil {
+		return
+	}
+
+	cancel()
+	<-done
+}
@@ -1,8 +1,11 @@
 package job
 
 import (
-	"github.com/stretchr/testify/assert"
+	"context"
 	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
 )
 
 func TestNewScheduledJob_ReturnErrorIfJobIsNil(t *testing.T) {
@@ -20,3 +23,38 @@ func TestNewScheduledJob_ReturnErrorIfIntervalIsLessThan1Second(t *testing.T) {
 	assert.Error(t, err)
 	assert.Equal(t, errInvalidInterval, err)
 }
+
+func TestStart_RunJobImmediately(t *testing.T) {
+	mockJob := &mockJob{}
+	job, _ := NewScheduledJob(mockJob, 1000)
+
+	job.Start()
+	defer job.Stop()
+
+	assert.Eventually(t, func() bool { return mockJob.runs.Load() == 1 }, time.Second, 10*time.Millisecond)
+}
+
+func TestStop_StopSchedulingJob(t *testing.T) {
+	mockJob := &mockJob{}
+	job, _ := NewScheduledJob(mockJob, 1000)
+
+	job.Start()
+	assert.Eventually(t, func() bool { return mockJob.runs.Load() == 1 }, time.Second, 10*time.Millisecond)
+	job.Stop()
+
+	time.Sleep(1100 * time.Millisecond)
+	assert.Equal(t, int32(1), mockJob.runs.Load())
+}
+
+func TestStartWithContext_StopSchedulingJobWhenContextIsCancelled(t *testing.T) {
+	mockJob := &mockJob{}
+	job, _ := NewScheduledJob(mockJob, 1000)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	job.StartWithContext(ctx)
+	assert.Eventually(t, func() bool { return mockJob.runs.Load() == 1 }, time.Second, 10*time.Millisecond)
+	cancel()
+
+	time.Sleep(1100 * time.Millisecond)
+	assert.Equal(t, int32(1), mockJob.runs.Load())
+}
@@ -0,0 +1,51 @@
+package telegram // import "heytobi.dev/fuse/telegram"
+
+import (
+	"context"
+)
+
+type accountService struct {
+	executor *executor
+}
+
+func newAccountService(executor *executor) (*accountService, error) {
+	return &accountService{
+		executor: executor,
+	}, nil
+}
+
+// getMe gets the user of the bot.
+// See https://core.telegram.org/bots/api#getme
+func (s *accountService) getMe(ctx context.Context) (*User, error) {
+	var me User
+	err := s.executor.execute(ctx, endpointGetMe, struct{}{}, &me)
+	if err != nil {
+		return nil, err
+	}
+
+	return &me, nil
+}
+
+// logOut logs the bot out from the cloud Bot API server, e.g. before running it on a local Bot API server.
+// See https://core.telegram.org/bots/api#logout
+func (s *accountService) logOut(ctx context.Context) (bool, error) {
+	var loggedOut bool
+	err := s.executor.execute(ctx, endpointLogOut, struct{}{}, &loggedOut)
+	if err != nil {
+		return false, err
+	}
+
+	return loggedOut, nil
+}
+
+// close closes the bot instance, e.g. before moving it from a local Bot API server to another.
+// See https://core.telegram.org/bots/api#close
+func (s *accountService) close(ctx context.Context) (bool, error) {
+	var closed bool
+	err := s.executor.execute(ctx, endpointClose, struct{}{}, &closed)
+	if err != nil {
+		return false, err
+	}
+
+	return closed, nil
+}
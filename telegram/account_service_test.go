@@ -0,0 +1,41 @@
+package telegram
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testGetMeResponse = `{"ok": true, "result": {"id": 42, "is_bot": true, "first_name": "Fuse", ` +
+	`"username": "fuse_bot", "can_join_groups": true, "can_read_all_group_messages": false, ` +
+	`"supports_inline_queries": true}}`
+
+func TestGetMe_ReturnUser(t *testing.T) {
+	httpClient, _ := newRecordingHttpClient(testGetMeResponse)
+	service, _ := newAccountService(newExecutor(httpClient, testApiUrlFmt, testToken, nil))
+
+	me, err := service.getMe(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, &User{
+		ID:                    42,
+		IsBot:                 true,
+		FirstName:             "Fuse",
+		Username:              "fuse_bot",
+		CanJoinGroups:         true,
+		SupportsInlineQueries: true,
+	}, me)
+}
+
+func TestGetMe_CacheUser(t *testing.T) {
+	httpClient, _ := newRecordingHttpClient(testGetMeResponse)
+	bot, _ := NewBot(&Config{Token: "test"}, httpClient)
+
+	assert.Nil(t, bot.Me())
+
+	me, err := bot.GetMe(context.Background())
+
+	assert.NoError(t, err)
+	assert.Same(t, me, bot.Me())
+}
@@ -0,0 +1,185 @@
+package telegram // import "heytobi.dev/fuse/telegram"
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const defaultAlbumWindow = 500 * time.Millisecond
+
+// AlbumHandlerFunc defines functions that can handle albums, i.e. the updates of messages sharing a media group ID,
+// ordered by message ID.
+type AlbumHandlerFunc func(ctx context.Context, album []*Update) error
+
+// AlbumCollector aggregates the messages of an album, which Telegram delivers as separate updates. Telegram doesn't
+// indicate how many messages an album has, so the collector buffers messages sharing a media group ID until no new one
+// has been received for a short window, and then hands them off to its handler at once.
+//
+// The updates of an album are only acknowledged once the album has been handled, so that when using
+// OffsetCommitOnAcknowledge, the offset isn't advanced past albums that are still being collected. The poller then
+// waits for albums to be handed off before requesting more updates, so the messages of an album received in separate
+// batches are handed off as separate albums. Albums being collected when the bot stops are handed off before Stop
+// returns.
+type AlbumCollector struct {
+	window  time.Duration
+	handler AlbumHandlerFunc
+	mu      sync.Mutex
+	albums  map[string]*pendingAlbum
+	// handingOff counts the albums being handed off, idle is signalled once it's back to 0.
+	handingOff int
+	idle       *sync.Cond
+}
+
+type pendingAlbum struct {
+	ctx              context.Context
+	updates          []*Update
+	acknowledgements []func()
+	timer            *time.Timer
+}
+
+// NewAlbumCollector initializes an AlbumCollector handing off albums to the given handler once no message has been
+// added to them for the given window, 500ms if the window isn't positive.
+func NewAlbumCollector(window time.Duration, handler AlbumHandlerFunc) *AlbumCollector {
+	if window <= 0 {
+		window = defaultAlbumWindow
+	}
+
+	collector := &AlbumCollector{
+		window:  window,
+		handler: handler,
+		albums:  make(map[string]*pendingAlbum),
+	}
+	collector.idle = sync.NewCond(&collector.mu)
+
+	return collector
+}
+
+// Middleware returns a middleware collecting the messages and channel posts that are part of an album, these updates
+// aren't passed on to the next handler. Other updates are passed on as usual.
+//
+// Albums are handed off asynchronously once their window elapses, outside of the worker processing the chat they were
+// sent to, so they might be handled after updates received later from the same chat, or concurrently with them.
+func (c *AlbumCollector) Middleware() Middleware {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(ctx context.Context, update *Update) error {
+			if !c.collect(ctx, update) {
+				return next(ctx, update)
+			}
+
+			return nil
+		}
+	}
+}
+
+// Flush hands off every album being collected without waiting for its window to elapse. It returns once the albums
+// have been handled, along with the albums already being handed off. Bot.Stop flushes the collector, so that albums
+// aren't lost when the bot stops.
+func (c *AlbumCollector) Flush() {
+	c.mu.Lock()
+	groupIDs := make([]string, 0, len(c.albums))
+	for groupID, album := range c.albums {
+		album.timer.Stop()
+		groupIDs = append(groupIDs, groupID)
+	}
+	c.mu.Unlock()
+
+	for _, groupID := range groupIDs {
+		c.handOff(groupID)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for c.handingOff > 0 {
+		c.idle.Wait()
+	}
+}
+
+// collect adds the given update to its album, it returns false if the update isn't part of an album.
+func (c *AlbumCollector) collect(ctx context.Context, update *Update) bool {
+	message := update.Message
+	if message == nil {
+		message = update.ChannelPost
+	}
+
+	if message == nil || message.MediaGroupID == "" {
+		return false
+	}
+
+	groupID := message.MediaGroupID
+
+	// the bot flushes the collector when it stops.
+	if bot, ok := ctx.Value(handlingBotContextKey).(*Bot); ok {
+		bot.albumCollectors.Store(c, struct{}{})
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	album, ok := c.albums[groupID]
+	if !ok {
+		album = &pendingAlbum{}
+		album.timer = time.AfterFunc(c.window, func() {
+			c.handOff(groupID)
+		})
+		c.albums[groupID] = album
+	} else {
+		album.timer.Reset(c.window)
+	}
+
+	album.ctx = ctx
+	album.updates = append(album.updates, update)
+	album.acknowledgements = append(album.acknowledgements, deferAcknowledgement(ctx))
+
+	return true
+}
+
+func (c *AlbumCollector) handOff(groupID string) {
+	c.mu.Lock()
+	album, ok := c.albums[groupID]
+	delete(c.albums, groupID)
+	if ok {
+		c.handingOff++
+	}
+	c.mu.Unlock()
+
+	// the album might have been handed off by Flush already.
+	if !ok {
+		return
+	}
+
+	defer func() {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+
+		c.handingOff--
+		if c.handingOff == 0 {
+			c.idle.Broadcast()
+		}
+	}()
+
+	sort.SliceStable(album.updates, func(i, j int) bool {
+		return albumMessageID(album.updates[i]) < albumMessageID(album.updates[j])
+	})
+
+	err := c.handler(album.ctx, album.updates)
+	if err != nil {
+		logrus.WithError(err).WithField("media_group_id", groupID).Error("failed to handle album")
+	}
+
+	for _, acknowledge := range album.acknowledgements {
+		acknowledge()
+	}
+}
+
+func albumMessageID(update *Update) int64 {
+	if update.Message != nil {
+		return update.Message.ID
+	}
+
+	return update.ChannelPost.ID
+}
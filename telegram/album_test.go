@@ -0,0 +1,142 @@
+package telegram
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAlbumCollector_HandOffAlbumOnceWindowElapses(t *testing.T) {
+	albums := make(chan []*Update, 1)
+	collector := NewAlbumCollector(20*time.Millisecond, func(ctx context.Context, album []*Update) error {
+		albums <- album
+		return nil
+	})
+	handler := collector.Middleware()(func(ctx context.Context, update *Update) error {
+		t.Error("album messages should not be passed on")
+		return nil
+	})
+
+	for _, id := range []int64{3, 1, 2} {
+		_ = handler(context.Background(), &Update{Message: &Message{ID: id, MediaGroupID: "album"}})
+	}
+
+	select {
+	case album := <-albums:
+		assert.Len(t, album, 3)
+		for i, update := range album {
+			assert.Equal(t, int64(i+1), update.Message.ID)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("album was not handed off")
+	}
+}
+
+func TestAlbumCollector_PassOnOtherUpdates(t *testing.T) {
+	collector := NewAlbumCollector(time.Hour, func(ctx context.Context, album []*Update) error {
+		return nil
+	})
+	var handled []*Update
+	handler := collector.Middleware()(func(ctx context.Context, update *Update) error {
+		handled = append(handled, update)
+		return nil
+	})
+
+	_ = handler(context.Background(), &Update{Message: &Message{ID: 1}})
+	_ = handler(context.Background(), &Update{CallbackQuery: &CallbackQuery{ID: "query"}})
+
+	assert.Len(t, handled, 2)
+}
+
+func TestAlbumCollector_FlushPendingAlbums(t *testing.T) {
+	var albums [][]*Update
+	collector := NewAlbumCollector(time.Hour, func(ctx context.Context, album []*Update) error {
+		albums = append(albums, album)
+		return nil
+	})
+	handler := collector.Middleware()(func(ctx context.Context, update *Update) error {
+		return nil
+	})
+
+	_ = handler(context.Background(), &Update{Message: &Message{ID: 1, MediaGroupID: "first"}})
+	_ = handler(context.Background(), &Update{ChannelPost: &Message{ID: 2, MediaGroupID: "second"}})
+	_ = handler(context.Background(), &Update{ChannelPost: &Message{ID: 3, MediaGroupID: "second"}})
+
+	collector.Flush()
+
+	assert.Len(t, albums, 2)
+	collector.Flush()
+	assert.Len(t, albums, 2)
+}
+
+func TestAlbumCollector_CollectAlbumsWithoutHandler(t *testing.T) {
+	var albums [][]*Update
+	collector := NewAlbumCollector(time.Hour, func(ctx context.Context, album []*Update) error {
+		albums = append(albums, album)
+		return nil
+	})
+	bot, _ := NewBot(&Config{Token: "test"}, &mockHttpClient{})
+	bot.Use(collector.Middleware())
+
+	for _, id := range []int64{1, 2} {
+		err := bot.ProcessUpdate(context.Background(), &Update{Message: &Message{ID: id, MediaGroupID: "album"}})
+		assert.NoError(t, err)
+	}
+	collector.Flush()
+
+	assert.Len(t, albums, 1)
+	assert.Len(t, albums[0], 2)
+}
+
+func TestAlbumCollector_AcknowledgeAlbumOnceHandled(t *testing.T) {
+	collector := NewAlbumCollector(time.Hour, func(ctx context.Context, album []*Update) error {
+		return nil
+	})
+	bot, _ := NewBot(&Config{Token: "test"}, &mockHttpClient{})
+	bot.Use(collector.Middleware())
+	_ = bot.RegisterDefaultHandler(func(ctx context.Context, update *Update) error { return nil })
+
+	updates := make(chan *Update, 2)
+	updates <- &Update{ID: 1, Message: &Message{ID: 1, MediaGroupID: "album"}}
+	updates <- &Update{ID: 2, Message: &Message{ID: 2}}
+	close(updates)
+
+	var acknowledged []int
+	bot.dispatch(context.Background(), updates, func(update *Update) {
+		acknowledged = append(acknowledged, update.ID)
+	})
+	assert.Equal(t, []int{2}, acknowledged)
+
+	collector.Flush()
+	assert.Equal(t, []int{2, 1}, acknowledged)
+}
+
+func TestAlbumCollector_HandOffPendingAlbumsWhenBotStops(t *testing.T) {
+	albums := make(chan []*Update, 1)
+	collector := NewAlbumCollector(time.Hour, func(ctx context.Context, album []*Update) error {
+		albums <- album
+		return nil
+	})
+	bot, _ := NewBot(&Config{Token: "test", UpdateMethod: UpdateMethodWebhook}, &mockHttpClient{})
+	bot.Use(collector.Middleware())
+	_ = bot.Start()
+
+	for _, id := range []string{"1", "2"} {
+		body := bytes.NewBufferString(`{"message": {"message_id": ` + id + `, "media_group_id": "album"}}`)
+		bot.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/", body))
+	}
+
+	assert.NoError(t, bot.Stop(context.Background()))
+
+	select {
+	case album := <-albums:
+		assert.Len(t, album, 2)
+	default:
+		t.Fatal("pending album was not handed off before the bot stopped")
+	}
+}
@@ -2,8 +2,12 @@ package telegram // import "heytobi.dev/fuse/telegram"
 
 import (
 	"context"
+	"io"
 	"net/http"
 	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
 
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
@@ -16,21 +20,51 @@ const (
 	defaultBotApiServer = "https://api.telegram.org"
 
 	httpPost = "POST"
+
+	webhookUpdatesBufferSize = 100
 )
 
 var (
 	errEmptyCommand            = errors.New("empty command")
 	errNilUpdate               = errors.New("update cannot be nil")
 	errNilMessageRequest       = errors.New("message cannot be nil")
+	errMissingFile             = errors.New("a file is required")
+	errInvalidMediaGroupSize   = errors.New("a media group must contain between 2 and 10 items")
+	errMissingFileID           = errors.New("a file id is required")
+	errInvalidMessageAddress   = errors.New("either a chat and message id, or an inline message id is required")
+	errInvalidDeleteCount      = errors.New("between 1 and 100 messages can be deleted at once")
+	errFileTooLarge            = errors.New("files larger than 20MB can only be downloaded through a local bot api server")
 	errMissingToken            = errors.New("missing API token")
 	errMissingWebhookUrl       = errors.New("a url is required to register a webhook")
+	errNilWebhook              = errors.New("webhook cannot be nil")
 	errNilHttpClient           = errors.New("an http client is required to initialize a Bot connection")
 	errNilPoller               = errors.New("a poller is required when using getUpdates")
 	errNilConfig               = errors.New("a configuration object is required to initialize a Bot connection")
 	errHandlerExists           = errors.New("an handler already exists for this command")
 	errInvalidUpdateMethod     = errors.New("invalid update method")
+	errInvalidPollingMode      = errors.New("invalid polling mode")
+	errInvalidOffsetCommitMode = errors.New("invalid offset commit mode")
+	errInvalidOrderingKey      = errors.New("invalid ordering key")
 	errDefaultHandlerExists    = errors.New("a default handler is already registered")
+	errUpdateHandlerExists     = errors.New("a handler is already registered for this update type")
+	errInvalidUpdateType       = errors.New("invalid update type")
+	errNilRoute                = errors.New("route cannot be nil")
+	errNilRouteHandler         = errors.New("a route requires a handler")
+	errEmptyCallbackTemplate   = errors.New("empty callback data template")
+	errCallbackHandlerExists   = errors.New("an handler already exists for this callback data template")
+	errNilCallbackAnswer       = errors.New("callback query answer cannot be nil")
+	errNoCallbackQuery         = errors.New("no callback query is being processed")
+	errNilInlineQueryAnswer    = errors.New("inline query answer cannot be nil")
+	errNilInlineQueryResult    = errors.New("inline query results cannot be nil")
+	errTooManyInlineResults    = errors.New("an inline query can be answered with at most 50 results")
+	errNilCommandsRequest      = errors.New("commands request cannot be nil")
+	errTooManyCommands         = errors.New("a bot can have at most 100 commands")
+	errInvalidCommandName      = errors.New("a command must be 1-32 lowercase letters, digits or underscores")
+	errInvalidDescription      = errors.New("a command description must be 1-256 characters long")
 	errWrongUpdateMethodConfig = errors.New("bot is not configured to use webhook update method")
+	errNilBot                  = errors.New("a bot is required to initialize a webhook server")
+	errNilWebhookServerConfig  = errors.New("a configuration object is required to initialize a webhook server")
+	errIncompleteTLSConfig     = errors.New("both a certificate and a key file are required to serve webhooks over TLS")
 )
 
 type httpClient interface {
@@ -38,15 +72,12 @@ type httpClient interface {
 }
 
 type poller interface {
-	start() error
+	start(ctx context.Context) error
+	stop()
+	acknowledge(update *Update)
 	getUpdatesChannel() <-chan *Update
 }
 
-type ActionResult struct {
-	Successful  bool
-	Description string
-}
-
 // Handler defines structs that can handle bot commands / messages.
 type Handler interface {
 	Handle(ctx context.Context, update *Update) error
@@ -61,23 +92,53 @@ type Config struct {
 	BotApiServerPort    int
 	Token               string
 	UpdateMethod        string
+	PollingMode         string
 	PollingIntervalMS   int64
 	PollingTimeout      int
 	PollingUpdatesLimit int
+	OffsetCommitMode    string
+	Workers             int
+	WorkerQueueSize     int
+	OrderingKey         string
 	AllowedUpdates      []string `json:"allowed_updates"`
+	WebhookSecretToken  string
+	Username            string
+	RetryPolicy         *RetryPolicy
+	PublishCommands     bool
+	GetMeOnStart        bool
 }
 
 // Bot defines the attributes of a Telegram Bot.
 type Bot struct {
-	config           *Config
-	httpClient       httpClient
-	handlers         map[string]HandlerFunc
-	defaultHandler   HandlerFunc
-	poller           poller
-	isRunning        bool
-	apiUrlFmt        string
-	messagingService *messagingService
-	webhookService   *webhookService
+	config             *Config
+	httpClient         httpClient
+	handlers           map[string]HandlerFunc
+	commands           []BotCommand
+	webhook            *Webhook
+	webhookSecretToken atomic.Pointer[string]
+	me                 atomic.Pointer[User]
+	defaultHandler     HandlerFunc
+	updateHandlers     map[string]HandlerFunc
+	routes             []*Route
+	callbackRoutes     []*callbackRoute
+	middleware         []Middleware
+	albumCollectors    sync.Map
+	poller             poller
+	isRunning          bool
+	mu                 sync.RWMutex
+	updatesChan        chan *Update
+	stopped            chan struct{}
+	dispatchDone       chan struct{}
+	cancelHandlers     context.CancelFunc
+	apiUrlFmt          string
+	executor           *executor
+	messagingService   *messagingService
+	webhookService     *webhookService
+	callbackService    *callbackService
+	inlineQueryService *inlineQueryService
+	commandService     *commandService
+	accountService     *accountService
+	fileService        *fileService
 }
 
 // NewBot initializes a Bot instance.
@@ -87,9 +148,15 @@ type Bot struct {
 // Note that it only defaults to getUpdates if no update method is specified, if an invalid one is configured,
 // an error is returned.
 //
+// Updates are processed by a pool of Workers, 1 by default. Updates from the same chat, or from the same user if the
+// OrderingKey is OrderByUser, are always processed sequentially and in order, regardless of the number of workers.
+//
+// Failed requests to the Bot API are not retried unless a RetryPolicy is configured.
+//
 // It returns an error if any of these conditions are met:
 //   - The given config is nil
-//   - The configured UpdateMethod is invalid.
+//   - The configured UpdateMethod is invalid
+//   - The configured OrderingKey is invalid.
 func NewBot(config *Config, httpClient httpClient) (*Bot, error) {
 	if config == nil {
 		return nil, errNilConfig
@@ -107,31 +174,82 @@ func NewBot(config *Config, httpClient httpClient) (*Bot, error) {
 		return nil, errInvalidUpdateMethod
 	}
 
+	if config.OrderingKey == "" {
+		config.OrderingKey = OrderByChat
+	}
+
+	if config.OrderingKey != OrderByChat && config.OrderingKey != OrderByUser {
+		return nil, errInvalidOrderingKey
+	}
+
 	if httpClient == nil {
 		return nil, errNilHttpClient
 	}
 
-	apiUrlFmt := deriveBotApiUrlBase(config) + "/bot%s/%s"
+	apiUrlBase := deriveBotApiUrlBase(config)
+	apiUrlFmt := apiUrlBase + "/bot%s/%s"
 
-	webhookService, err := newWebhookService(httpClient, apiUrlFmt, config.Token, config.AllowedUpdates)
+	executor := newExecutor(httpClient, apiUrlFmt, config.Token, config.RetryPolicy)
+
+	webhookService, err := newWebhookService(executor, config.AllowedUpdates)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to initialize webhook service")
 	}
 
-	messagingService, err := newMessagingService(httpClient, apiUrlFmt, config.Token)
+	messagingService, err := newMessagingService(executor)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to initialize messaging service")
 	}
 
+	callbackService, err := newCallbackService(executor)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to initialize callback service")
+	}
+
+	inlineQueryService, err := newInlineQueryService(executor)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to initialize inline query service")
+	}
+
+	commandService, err := newCommandService(executor)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to initialize command service")
+	}
+
+	accountService, err := newAccountService(executor)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to initialize account service")
+	}
+
+	fileService, err := newFileService(
+		executor,
+		httpClient,
+		apiUrlBase+"/file/bot%s/%s",
+		config.Token,
+		config.BotApiServer != "",
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to initialize file service")
+	}
+
 	bot := &Bot{
-		config:           config,
-		httpClient:       httpClient,
-		handlers:         make(map[string]HandlerFunc),
-		apiUrlFmt:        apiUrlFmt,
-		messagingService: messagingService,
-		webhookService:   webhookService,
+		config:             config,
+		httpClient:         httpClient,
+		handlers:           make(map[string]HandlerFunc),
+		updateHandlers:     make(map[string]HandlerFunc),
+		apiUrlFmt:          apiUrlFmt,
+		executor:           executor,
+		messagingService:   messagingService,
+		webhookService:     webhookService,
+		callbackService:    callbackService,
+		inlineQueryService: inlineQueryService,
+		commandService:     commandService,
+		accountService:     accountService,
+		fileService:        fileService,
 	}
 
+	bot.webhookSecretToken.Store(&config.WebhookSecretToken)
+
 	return bot, nil
 }
 
@@ -141,53 +259,294 @@ func (b *Bot) WithPoller(p poller) *Bot {
 	return b
 }
 
-// Start starts the process of polling for updates from Telegram.
+// WithWebhook sets the webhook the bot receives updates through when using the webhook update method. When the bot
+// starts, the webhook is only registered if the registered webhook differs from it, i.e. if its url, its allowed
+// updates or, if set, its ip address or maximum number of connections differ, or if the registered webhook has a
+// certificate, so that restarting several instances of the bot doesn't register the webhook over and over, which could
+// drop updates. Since Telegram doesn't report the certificate, the webhook is always registered if it has one. Neither
+// does it report the secret token, so the webhook is also registered if Telegram reports that updates are rejected as
+// unauthorized, e.g. after changing the secret token.
+func (b *Bot) WithWebhook(webhook *Webhook) *Bot {
+	b.webhook = webhook
+	return b
+}
+
+// WithRateLimiter sets the rate limiter pacing the messages sent by the bot, e.g. a FloodLimiter to stay within
+// Telegram's flood limits. Messages are not paced by default.
+func (b *Bot) WithRateLimiter(limiter RateLimiter) *Bot {
+	b.executor.rateLimiter = limiter
+	return b
+}
+
+// Start starts the process of receiving updates from Telegram.
+// When using getUpdates, the poller is started. When using a webhook, updates received through ServeHTTP are
+// dispatched to the registered handlers.
 func (b *Bot) Start() error {
-	ctx := context.Background()
+	return b.StartWithContext(context.Background())
+}
+
+// StartWithContext starts the process of receiving updates from Telegram, like Start. Cancelling the given context
+// stops the bot, as calling Stop would.
+//
+// The context passed to handlers carries the values of the given context, but is only cancelled if Stop gives up on
+// waiting for in-flight handlers.
+//
+// If GetMeOnStart is enabled in the config, the identity of the bot is fetched first, failing fast if the token is
+// invalid. If PublishCommands is enabled, the commands registered through RegisterCommand replace the commands shown in
+// the menu of the bot before updates are received. When using a webhook set through WithWebhook, the webhook is
+// registered if the registered webhook differs from it.
+func (b *Bot) StartWithContext(ctx context.Context) error {
+	b.mu.RLock()
+	isRunning := b.isRunning
+	b.mu.RUnlock()
+
+	if isRunning {
+		return nil
+	}
+
+	// the requests preparing the bot are sent without holding the lock, so that ServeHTTP isn't blocked meanwhile.
+	err := b.prepareStart(ctx)
+	if err != nil {
+		return err
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.isRunning {
+		return nil
+	}
 
-	if b.config.UpdateMethod == UpdateMethodGetUpdates && !b.isRunning {
-		if b.poller == nil {
-			return errNilPoller
+	var updates <-chan *Update
+	acknowledge := func(*Update) {}
+	if b.config.UpdateMethod == UpdateMethodWebhook {
+		b.updatesChan = make(chan *Update, webhookUpdatesBufferSize)
+		updates = b.updatesChan
+	} else {
+		err = b.poller.start(ctx)
+		if err != nil {
+			return errors.Wrap(err, "failed to start poller")
 		}
 
-		updatesChan := b.poller.getUpdatesChannel()
-		go func() {
-			for update := range updatesChan {
-				err := b.ProcessUpdate(ctx, update)
-				if err != nil {
-					logrus.WithError(err).Error("failed to process update")
-				}
+		updates = b.poller.getUpdatesChannel()
+		acknowledge = b.poller.acknowledge
+	}
+
+	handlersCtx, cancelHandlers := context.WithCancel(context.WithoutCancel(ctx))
+	handlersCtx = context.WithValue(handlersCtx, handlingBotContextKey, b)
+	dispatchDone := make(chan struct{})
+	stopped := make(chan struct{})
+
+	go func() {
+		defer close(dispatchDone)
+		b.dispatch(handlersCtx, updates, acknowledge)
+	}()
+
+	go func() {
+		select {
+		case <-ctx.Done():
+			err := b.Stop(context.Background())
+			if err != nil {
+				logrus.WithError(err).Error("failed to stop bot")
 			}
-		}()
+		case <-stopped:
+		}
+	}()
 
-		_, err := b.webhookService.deleteWebhook(false)
+	b.cancelHandlers = cancelHandlers
+	b.dispatchDone = dispatchDone
+	b.stopped = stopped
+	b.isRunning = true
+
+	return nil
+}
+
+// prepareStart sends the requests that have to complete before the bot starts receiving updates: fetching the identity
+// of the bot, publishing its commands, and syncing its webhook or deleting it when using getUpdates.
+func (b *Bot) prepareStart(ctx context.Context) error {
+	if b.config.UpdateMethod == UpdateMethodGetUpdates && b.poller == nil {
+		return errNilPoller
+	}
+
+	if b.config.GetMeOnStart {
+		_, err := b.GetMe(ctx)
 		if err != nil {
-			return errors.Wrap(err, "failed to delete webhook")
+			return errors.Wrap(err, "failed to get bot identity")
 		}
+	}
 
-		err = b.poller.start()
+	if b.config.PublishCommands {
+		err := b.publishCommands(ctx)
 		if err != nil {
-			return errors.Wrap(err, "failed to start poller")
+			return errors.Wrap(err, "failed to publish commands")
 		}
+	}
 
-		b.isRunning = true
+	if b.config.UpdateMethod == UpdateMethodGetUpdates {
+		_, err := b.webhookService.deleteWebhook(ctx, false)
+		if err != nil {
+			return errors.Wrap(err, "failed to delete webhook")
+		}
+	} else if b.webhook != nil {
+		err := b.syncWebhook(ctx)
+		if err != nil {
+			return errors.Wrap(err, "failed to sync webhook")
+		}
 	}
 
 	return nil
 }
 
+// Stop stops receiving updates from Telegram and waits for the updates already received to be processed.
+// If the given context is done before all in-flight handlers complete, the context passed to those handlers is
+// cancelled and an error is returned. Calling Stop on a bot that isn't running is a no-op.
+//
+// A handler can stop the bot by calling Stop with the context it was given, in which case Stop returns without waiting
+// for in-flight handlers, since it would otherwise wait for the calling handler itself. Calling Stop from a handler
+// with any other context blocks until that context is done.
+func (b *Bot) Stop(ctx context.Context) error {
+	b.mu.Lock()
+	if !b.isRunning {
+		b.mu.Unlock()
+		return nil
+	}
+
+	b.isRunning = false
+	close(b.stopped)
+
+	if b.config.UpdateMethod == UpdateMethodWebhook {
+		// ServeHTTP only queues updates while holding a read lock, so no more sends can happen at this point.
+		close(b.updatesChan)
+	} else {
+		b.poller.stop()
+	}
+
+	dispatchDone, cancelHandlers := b.dispatchDone, b.cancelHandlers
+	b.mu.Unlock()
+
+	processed := b.awaitProcessing(dispatchDone)
+
+	if handlingBot, ok := ctx.Value(handlingBotContextKey).(*Bot); ok && handlingBot == b {
+		go func() {
+			<-processed
+			cancelHandlers()
+		}()
+		return nil
+	}
+
+	defer cancelHandlers()
+
+	select {
+	case <-processed:
+		return nil
+	case <-ctx.Done():
+		return errors.Wrap(ctx.Err(), "failed to process in-flight updates before stopping")
+	}
+}
+
+// awaitProcessing returns a channel closed once the updates received have been processed, i.e. once dispatchDone is
+// closed and the albums being collected by the bot's album collectors have been handed off.
+func (b *Bot) awaitProcessing(dispatchDone <-chan struct{}) <-chan struct{} {
+	processed := make(chan struct{})
+
+	go func() {
+		defer close(processed)
+		<-dispatchDone
+
+		b.albumCollectors.Range(func(collector, _ any) bool {
+			collector.(*AlbumCollector).Flush()
+			return true
+		})
+	}()
+
+	return processed
+}
+
 // RegisterWebhook registers the given webhook to listen for updates.
 // Returns the result of the request, True on success.
+//
+// If the webhook has no SecretToken, the configured WebhookSecretToken is used, or one is derived from the bot token,
+// which is the same for every instance of the bot. Once the webhook is registered, ServeHTTP rejects requests without
+// a matching X-Telegram-Bot-Api-Secret-Token header.
 // See https://core.telegram.org/bots/api#setwebhook
-func (b *Bot) RegisterWebhook(webhook *Webhook) (bool, error) {
+func (b *Bot) RegisterWebhook(ctx context.Context, webhook *Webhook) (bool, error) {
 	if b.config.UpdateMethod == UpdateMethodGetUpdates {
 		return false, errWrongUpdateMethodConfig
 	}
-	return b.webhookService.registerWebhook(webhook)
+
+	webhook, err := b.withSecretToken(webhook)
+	if err != nil {
+		return false, err
+	}
+
+	registered, err := b.webhookService.registerWebhook(ctx, webhook)
+	if err != nil || !registered {
+		return registered, err
+	}
+
+	b.webhookSecretToken.Store(&webhook.SecretToken)
+
+	return true, nil
+}
+
+// syncWebhook registers the webhook set through WithWebhook if the registered webhook differs from it.
+func (b *Bot) syncWebhook(ctx context.Context) error {
+	webhook, err := b.withSecretToken(b.webhook)
+	if err != nil {
+		return err
+	}
+
+	_, err = b.webhookService.syncWebhook(ctx, webhook)
+	if err != nil {
+		return err
+	}
+
+	secretToken := b.webhookService.secretToken(webhook)
+	b.webhookSecretToken.Store(&secretToken)
+
+	return nil
+}
+
+// withSecretToken returns a copy of the given webhook with its own secret token, or the configured one. Webhooks
+// without either are registered with a secret token derived from the bot token.
+func (b *Bot) withSecretToken(webhook *Webhook) (*Webhook, error) {
+	if webhook == nil {
+		return nil, errNilWebhook
+	}
+
+	withToken := *webhook
+	if withToken.SecretToken == "" {
+		withToken.SecretToken = b.config.WebhookSecretToken
+	}
+
+	return &withToken, nil
+}
+
+// GetWebhookInfo gets the status of the webhook of the bot, such as the number of pending updates or the last error
+// that happened while delivering updates. Its url is empty if no webhook is registered.
+// See https://core.telegram.org/bots/api#getwebhookinfo
+func (b *Bot) GetWebhookInfo(ctx context.Context) (*WebhookInfo, error) {
+	return b.webhookService.getWebhookInfo(ctx)
+}
+
+// LogOut logs the bot out from the cloud Bot API server, which has to be done before running the bot on a local Bot
+// API server. The bot can't log in to the cloud Bot API server again for 10 minutes. Returns True on success.
+// See https://core.telegram.org/bots/api#logout
+func (b *Bot) LogOut(ctx context.Context) (bool, error) {
+	return b.accountService.logOut(ctx)
 }
 
-// RegisterDefaultHandler registers the given handler function as the default. The default handler handles all
-// updates that don't match a specific command that is assigned its own handler in RegisterHandler.
+// Close closes the bot instance, which has to be done before moving the bot from a local Bot API server to another.
+// The webhook should be deleted before closing the instance, so that the bot isn't launched again. Returns True on
+// success.
+// See https://core.telegram.org/bots/api#close
+func (b *Bot) Close(ctx context.Context) (bool, error) {
+	return b.accountService.close(ctx)
+}
+
+// RegisterDefaultHandler registers the given handler function as the default. The default handler handles all messages
+// that don't match a specific command that is assigned its own handler in RegisterHandler. Other types of updates are
+// handled by the handlers registered through RegisterUpdateHandler.
 func (b *Bot) RegisterDefaultHandler(handler HandlerFunc) error {
 	if b.defaultHandler != nil {
 		return errDefaultHandlerExists
@@ -198,7 +557,7 @@ func (b *Bot) RegisterDefaultHandler(handler HandlerFunc) error {
 	return nil
 }
 
-// RegisterHandler registers the given handler function to handle invocations of the given command.
+// RegisterHandler registers the given handler function to handle invocations of the given command, e.g. /start.
 func (b *Bot) RegisterHandler(command string, handlerFunc HandlerFunc) error {
 	if command == "" {
 		return errEmptyCommand
@@ -214,36 +573,309 @@ func (b *Bot) RegisterHandler(command string, handlerFunc HandlerFunc) error {
 }
 
 // ProcessUpdate processes updates from telegram.
+// The handler matching the update is invoked through the middleware registered with Use. Updates that no handler
+// matches still go through the middleware, which then invokes a handler doing nothing.
+//
+// Messages starting with a command are routed to the handler registered for that command, regardless of the command's
+// arguments or bot mention. The parsed command is available to handlers through CommandFromContext. If the username of
+// the bot is known, either configured as Username or fetched through GetMe, commands addressed to other bots, e.g.
+// /help@OtherBot, are ignored.
+//
+// Telegram shows a loading indicator on the pressed button until a callback query is answered. Callback queries that
+// aren't answered while they're processed, through AnswerCallbackQueryFromContext or Bot.AnswerCallbackQuery, are
+// answered without a notification once processed, even if no handler matched them or a middleware stopped their
+// processing.
 func (b *Bot) ProcessUpdate(ctx context.Context, update *Update) error {
 	if update == nil {
 		return errNilUpdate
 	}
 
+	if update.CallbackQuery != nil {
+		answer := &callbackAnswer{bot: b, queryID: update.CallbackQuery.ID}
+		ctx = context.WithValue(ctx, callbackAnswerContextKey, answer)
+		defer answer.answerIfPending(ctx)
+	}
+
 	if update.Message != nil {
-		if handler, hasHandler := b.handlers[update.Message.Text]; hasHandler {
-			if handler != nil {
-				err := handler(ctx, update)
-				if err != nil {
-					return err
-				}
+		if command := parseCommand(update.Message); command != nil {
+			if !b.isAddressedToBot(command) {
+				return nil
 			}
-			return nil
+			ctx = context.WithValue(ctx, commandContextKey, command)
 		}
+	}
 
-		if b.defaultHandler != nil {
-			err := b.defaultHandler(ctx, update)
-			if err != nil {
-				return err
+	ctx, handler := b.resolveHandler(ctx, update)
+	if handler == nil {
+		handler = ignoreUpdate
+	}
+
+	return chain(handler, b.middleware)(ctx, update)
+}
+
+// ignoreUpdate is the handler of updates that no registered handler matches.
+func ignoreUpdate(context.Context, *Update) error {
+	return nil
+}
+
+// resolveHandler returns the handler registered for the given update, or nil if there is none, along with the context
+// the handler should be invoked with.
+// Messages are matched against command handlers first, and callback queries against callback handlers, then against
+// routes, falling back to the default handler for messages or the handler registered for their type otherwise.
+func (b *Bot) resolveHandler(ctx context.Context, update *Update) (context.Context, HandlerFunc) {
+	if update.Message != nil {
+		if command, isCommand := CommandFromContext(ctx); isCommand {
+			if handler, hasHandler := b.handlers[command.Name]; hasHandler {
+				return ctx, handler
 			}
 		}
+
+		if handler, hasHandler := b.handlers[update.Message.Text]; hasHandler {
+			return ctx, handler
+		}
 	}
 
-	return nil
+	if update.CallbackQuery != nil {
+		if route, params := b.matchCallbackRoute(update.CallbackQuery.Data); route != nil {
+			return context.WithValue(ctx, callbackParamsContextKey, params), route.handler
+		}
+	}
+
+	if route := b.matchRoute(update); route != nil {
+		return ctx, route.Handler
+	}
+
+	if update.Message != nil {
+		return ctx, b.defaultHandler
+	}
+
+	return ctx, b.updateHandlers[update.Type()]
+}
+
+// isAddressedToBot checks if the given command is addressed to this bot, commands without a mention are addressed to
+// every bot in the chat.
+func (b *Bot) isAddressedToBot(command *Command) bool {
+	username := strings.TrimPrefix(b.config.Username, "@")
+	if me := b.me.Load(); username == "" && me != nil {
+		username = me.Username
+	}
+
+	if command.Mention == "" || username == "" {
+		return true
+	}
+
+	return strings.EqualFold(command.Mention, username)
+}
+
+// dispatch processes every update received on the given channel until it is closed, calling acknowledge once each
+// update has been processed, or later if the acknowledgement of the update was deferred.
+func (b *Bot) dispatch(ctx context.Context, updates <-chan *Update, acknowledge func(*Update)) {
+	d := newDispatcher(b.config.Workers, b.config.WorkerQueueSize, b.config.OrderingKey, func(update *Update) {
+		ack := &acknowledgement{acknowledge: func() { acknowledge(update) }}
+
+		err := b.ProcessUpdate(context.WithValue(ctx, acknowledgementContextKey, ack), update)
+		if err != nil {
+			logrus.WithError(err).Error("failed to process update")
+		}
+
+		if !ack.deferred.Load() {
+			acknowledge(update)
+		}
+	})
+
+	d.run(updates)
+}
+
+// acknowledgement is the acknowledgement of an update being dispatched.
+type acknowledgement struct {
+	acknowledge func()
+	deferred    atomic.Bool
+}
+
+// deferAcknowledgement defers the acknowledgement of the update being processed with the given context until the
+// returned function is called, e.g. by a middleware buffering updates until they can be handled. The returned function
+// does nothing if the update isn't being dispatched by a running bot.
+func deferAcknowledgement(ctx context.Context) func() {
+	ack, ok := ctx.Value(acknowledgementContextKey).(*acknowledgement)
+	if !ok {
+		return func() {}
+	}
+
+	ack.deferred.Store(true)
+
+	return ack.acknowledge
+}
+
+// SendMessage sends a text message, returning the message as sent.
+// See https://core.telegram.org/bots/api#sendmessage
+func (b *Bot) SendMessage(ctx context.Context, message *SendMessageRequest) (*Message, error) {
+	return b.messagingService.sendMessage(ctx, message)
+}
+
+// SendPhoto sends a photo, returning the message as sent.
+// See https://core.telegram.org/bots/api#sendphoto
+func (b *Bot) SendPhoto(ctx context.Context, request *SendPhotoRequest) (*Message, error) {
+	return b.messagingService.sendPhoto(ctx, request)
+}
+
+// SendDocument sends a general file, returning the message as sent.
+// See https://core.telegram.org/bots/api#senddocument
+func (b *Bot) SendDocument(ctx context.Context, request *SendDocumentRequest) (*Message, error) {
+	return b.messagingService.sendDocument(ctx, request)
+}
+
+// SendAudio sends an audio file, to be displayed in the music player, returning the message as sent.
+// See https://core.telegram.org/bots/api#sendaudio
+func (b *Bot) SendAudio(ctx context.Context, request *SendAudioRequest) (*Message, error) {
+	return b.messagingService.sendAudio(ctx, request)
+}
+
+// SendVideo sends a video, returning the message as sent.
+// See https://core.telegram.org/bots/api#sendvideo
+func (b *Bot) SendVideo(ctx context.Context, request *SendVideoRequest) (*Message, error) {
+	return b.messagingService.sendVideo(ctx, request)
+}
+
+// SendVoice sends a voice note, returning the message as sent.
+// See https://core.telegram.org/bots/api#sendvoice
+func (b *Bot) SendVoice(ctx context.Context, request *SendVoiceRequest) (*Message, error) {
+	return b.messagingService.sendVoice(ctx, request)
+}
+
+// SendAnimation sends an animation, i.e. a GIF or a video without sound, returning the message as sent.
+// See https://core.telegram.org/bots/api#sendanimation
+func (b *Bot) SendAnimation(ctx context.Context, request *SendAnimationRequest) (*Message, error) {
+	return b.messagingService.sendAnimation(ctx, request)
+}
+
+// SendMediaGroup sends an album of photos, videos, audio files or documents, returning the messages as sent.
+// See https://core.telegram.org/bots/api#sendmediagroup
+func (b *Bot) SendMediaGroup(ctx context.Context, request *SendMediaGroupRequest) ([]*Message, error) {
+	return b.messagingService.sendMediaGroup(ctx, request)
+}
+
+// EditMessageText edits the text of a message, returning the edited message, or nil if the message was sent through
+// an inline query. Editing a message without changing it returns an error matching ErrMessageNotModified.
+// See https://core.telegram.org/bots/api#editmessagetext
+func (b *Bot) EditMessageText(ctx context.Context, request *EditMessageTextRequest) (*Message, error) {
+	return b.messagingService.editMessageText(ctx, request)
+}
+
+// EditMessageCaption edits the caption of a message, returning the edited message, or nil if the message was sent
+// through an inline query. Editing a message without changing it returns an error matching ErrMessageNotModified.
+// See https://core.telegram.org/bots/api#editmessagecaption
+func (b *Bot) EditMessageCaption(ctx context.Context, request *EditMessageCaptionRequest) (*Message, error) {
+	return b.messagingService.editMessageCaption(ctx, request)
+}
+
+// EditMessageMedia replaces the animation, audio, document, photo or video of a message, returning the edited
+// message, or nil if the message was sent through an inline query.
+// See https://core.telegram.org/bots/api#editmessagemedia
+func (b *Bot) EditMessageMedia(ctx context.Context, request *EditMessageMediaRequest) (*Message, error) {
+	return b.messagingService.editMessageMedia(ctx, request)
+}
+
+// EditMessageReplyMarkup edits the inline keyboard of a message, returning the edited message, or nil if the message
+// was sent through an inline query. Editing a message without changing it returns an error matching
+// ErrMessageNotModified.
+// See https://core.telegram.org/bots/api#editmessagereplymarkup
+func (b *Bot) EditMessageReplyMarkup(ctx context.Context, request *EditMessageReplyMarkupRequest) (*Message, error) {
+	return b.messagingService.editMessageReplyMarkup(ctx, request)
+}
+
+// DeleteMessage deletes a message, returning true on success.
+// See https://core.telegram.org/bots/api#deletemessage
+func (b *Bot) DeleteMessage(ctx context.Context, request *DeleteMessageRequest) (bool, error) {
+	return b.messagingService.deleteMessage(ctx, request)
+}
+
+// DeleteMessages deletes up to 100 messages of the same chat, returning true on success.
+// See https://core.telegram.org/bots/api#deletemessages
+func (b *Bot) DeleteMessages(ctx context.Context, request *DeleteMessagesRequest) (bool, error) {
+	return b.messagingService.deleteMessages(ctx, request)
+}
+
+// AnswerCallbackQuery answers a callback query sent from an inline keyboard, dismissing the loading indicator of the
+// pressed button and optionally showing a notification or an alert to the user. Returns True on success.
+// See https://core.telegram.org/bots/api#answercallbackquery
+func (b *Bot) AnswerCallbackQuery(ctx context.Context, answer *AnswerCallbackQueryRequest) (bool, error) {
+	result, err := b.callbackService.answerCallbackQuery(ctx, answer)
+
+	// mark the query being processed as answered, so that it isn't answered again once its handler returns.
+	if current, ok := ctx.Value(callbackAnswerContextKey).(*callbackAnswer); ok && err == nil {
+		if current.queryID == answer.CallbackQueryID {
+			current.answered.Store(true)
+		}
+	}
+
+	return result, err
+}
+
+// AnswerInlineQuery sends up to 50 results answering an inline query. Returns True on success.
+// See https://core.telegram.org/bots/api#answerinlinequery
+func (b *Bot) AnswerInlineQuery(ctx context.Context, answer *AnswerInlineQueryRequest) (bool, error) {
+	return b.inlineQueryService.answerInlineQuery(ctx, answer)
+}
+
+// GetMe gets the user of the bot, which identifies it and tells whether it can join groups, read all group messages
+// or be queried inline. The user is cached and available through Me afterwards. The token of the bot is invalid if the
+// returned error is an *APIError with a 401 error code.
+// See https://core.telegram.org/bots/api#getme
+func (b *Bot) GetMe(ctx context.Context) (*User, error) {
+	me, err := b.accountService.getMe(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	b.me.Store(me)
+
+	return me, nil
+}
+
+// Me returns the user of the bot as last fetched through GetMe, or nil if it hasn't been fetched yet.
+func (b *Bot) Me() *User {
+	return b.me.Load()
+}
+
+// SetMyCommands sets the list of commands shown in the menu of the bot for the given scope and language. Returns True
+// on success.
+// See https://core.telegram.org/bots/api#setmycommands
+func (b *Bot) SetMyCommands(ctx context.Context, request *SetMyCommandsRequest) (bool, error) {
+	return b.commandService.setMyCommands(ctx, request)
+}
+
+// GetMyCommands gets the list of commands shown in the menu of the bot for the given scope and language, a nil
+// request gets the commands of the default scope.
+// See https://core.telegram.org/bots/api#getmycommands
+func (b *Bot) GetMyCommands(ctx context.Context, request *GetMyCommandsRequest) ([]BotCommand, error) {
+	return b.commandService.getMyCommands(ctx, request)
+}
+
+// DeleteMyCommands deletes the list of commands shown in the menu of the bot for the given scope and language, a nil
+// request deletes the commands of the default scope. Returns True on success.
+// See https://core.telegram.org/bots/api#deletemycommands
+func (b *Bot) DeleteMyCommands(ctx context.Context, request *DeleteMyCommandsRequest) (bool, error) {
+	return b.commandService.deleteMyCommands(ctx, request)
+}
+
+// GetFile gets the information needed to download the file with the given ID.
+// See https://core.telegram.org/bots/api#getfile
+func (b *Bot) GetFile(ctx context.Context, fileID string) (*File, error) {
+	return b.fileService.getFile(ctx, fileID)
+}
+
+// FileDownloadURL returns the URL the given file can be downloaded from, the URL contains the bot's token so it must
+// not be shared. It's only valid for about an hour once the file was obtained through GetFile.
+func (b *Bot) FileDownloadURL(file *File) string {
+	return b.fileService.downloadURL(file)
 }
 
-// SendMessage sends a message to the user.
-func (b *Bot) SendMessage(message *SendMessageRequest) (*ActionResult, error) {
-	return b.messagingService.sendMessage(message)
+// DownloadFile downloads the file with the given ID, writing its content to the given writer as it's downloaded.
+// Files larger than 20MB can only be downloaded when using a local Bot API server. When the local server runs in
+// --local mode, files are read from the path on its filesystem, which must be accessible to the bot.
+// See https://core.telegram.org/bots/api#getfile
+func (b *Bot) DownloadFile(ctx context.Context, fileID string, writer io.Writer) error {
+	return b.fileService.downloadFile(ctx, fileID, writer)
 }
 
 func deriveBotApiUrlBase(config *Config) string {
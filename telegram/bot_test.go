@@ -6,8 +6,11 @@ import (
 	"encoding/json"
 	"io"
 	"net/http"
+	"net/http/httptest"
 	"strconv"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
@@ -112,32 +115,25 @@ func TestStart_StartSuccessfully(t *testing.T) {
 
 func TestSendMessage_ReturnErrorIfMessageIsNil(t *testing.T) {
 	bot, _ := NewBot(&Config{Token: "test"}, &mockHttpClient{})
-	result, err := bot.SendMessage(nil)
+	result, err := bot.SendMessage(context.Background(), nil)
 
 	assert.Error(t, err)
 	assert.Equal(t, errNilMessageRequest, err)
-	assert.False(t, result.Successful)
+	assert.Nil(t, result)
 }
 
 func TestSendMessage_SendSuccessfully(t *testing.T) {
-	response := sendMessageResponse{Ok: true}
-	responseJson, _ := json.Marshal(response)
-	body := io.NopCloser(bytes.NewBuffer(responseJson))
-
-	httpClient := &mockHttpClient{}
-	httpClient.On("Do", mock.Anything, mock.Anything).Return(&http.Response{
-		StatusCode: http.StatusOK,
-		Body:       body,
-	}, nil)
+	httpClient, _ := newRecordingHttpClient(`{"ok": true, "result": {"message_id": 42, "chat": {"id": 7}}}`)
 
 	bot, _ := NewBot(&Config{Token: "test"}, httpClient)
-	result, err := bot.SendMessage(&SendMessageRequest{
-		ChatID: 0,
+	result, err := bot.SendMessage(context.Background(), &SendMessageRequest{
+		ChatID: 7,
 		Text:   "test",
 	})
 
-	assert.True(t, result.Successful)
 	assert.NoError(t, err)
+	assert.Equal(t, int64(42), result.ID)
+	assert.Equal(t, int64(7), result.Chat.ID)
 }
 
 func TestRegisterHandler_RegisterHandlerSuccessfully(t *testing.T) {
@@ -181,7 +177,7 @@ func TestRegisterWebhook_ReturnErrorIfUrlIsEmpty(t *testing.T) {
 	httpClient.On("Do", mock.Anything, mock.Anything).Return(&http.Response{}, errors.New("failed"))
 
 	bot, _ := NewBot(&Config{Token: "test", UpdateMethod: UpdateMethodWebhook}, httpClient)
-	registered, err := bot.RegisterWebhook(&Webhook{Url: ""})
+	registered, err := bot.RegisterWebhook(context.Background(), &Webhook{Url: ""})
 
 	assert.Error(t, err)
 	assert.Equal(t, errMissingWebhookUrl, err)
@@ -190,7 +186,7 @@ func TestRegisterWebhook_ReturnErrorIfUrlIsEmpty(t *testing.T) {
 
 func TestRegisterWebhook_ReturnErrorIfUpdateMethodIsNotWebhook(t *testing.T) {
 	bot, _ := NewBot(&Config{Token: "test"}, &mockHttpClient{})
-	registered, err := bot.RegisterWebhook(&Webhook{Url: "url.test"})
+	registered, err := bot.RegisterWebhook(context.Background(), &Webhook{Url: "url.test"})
 
 	assert.Error(t, err)
 	assert.Equal(t, errWrongUpdateMethodConfig, err)
@@ -202,14 +198,14 @@ func TestRegisterWebhook_ReturnErrorIfApiRequestFails(t *testing.T) {
 	httpClient.On("Do", mock.Anything, mock.Anything).Return(&http.Response{}, errors.New("failed"))
 
 	bot, _ := NewBot(&Config{Token: "test", UpdateMethod: UpdateMethodWebhook}, httpClient)
-	result, err := bot.RegisterWebhook(&Webhook{Url: "webhook.url"})
+	result, err := bot.RegisterWebhook(context.Background(), &Webhook{Url: "webhook.url"})
 
 	assert.Error(t, err)
 	assert.False(t, result)
 }
 
 func TestRegisterWebhook_RegisterSuccessfully(t *testing.T) {
-	response := webhookResponse{Ok: true}
+	response := apiResponse{Ok: true, Result: json.RawMessage(`true`)}
 	json, _ := json.Marshal(response)
 	body := io.NopCloser(bytes.NewBuffer(json))
 
@@ -220,14 +216,14 @@ func TestRegisterWebhook_RegisterSuccessfully(t *testing.T) {
 	}, nil)
 
 	bot, _ := NewBot(&Config{Token: "test", UpdateMethod: UpdateMethodWebhook}, httpClient)
-	result, err := bot.RegisterWebhook(&Webhook{Url: "webhook.url"})
+	result, err := bot.RegisterWebhook(context.Background(), &Webhook{Url: "webhook.url"})
 
 	assert.True(t, result)
 	assert.NoError(t, err)
 }
 
 func TestRegisterWebhook_ReturnFalseIfResponseResultIsFalse(t *testing.T) {
-	response := webhookResponse{Ok: false}
+	response := apiResponse{Ok: true, Result: json.RawMessage(`false`)}
 	responseJson, _ := json.Marshal(response)
 	body := io.NopCloser(bytes.NewBuffer(responseJson))
 
@@ -238,7 +234,7 @@ func TestRegisterWebhook_ReturnFalseIfResponseResultIsFalse(t *testing.T) {
 	}, nil)
 
 	bot, _ := NewBot(&Config{Token: "test", UpdateMethod: UpdateMethodWebhook}, httpClient)
-	result, err := bot.RegisterWebhook(&Webhook{Url: "webhook.url"})
+	result, err := bot.RegisterWebhook(context.Background(), &Webhook{Url: "webhook.url"})
 
 	assert.False(t, result)
 	assert.NoError(t, err)
@@ -260,3 +256,187 @@ func TestProcessUpdate_DontReturnErrorIfGivenValidUpdateType(t *testing.T) {
 
 	assert.NoError(t, err)
 }
+
+func TestStart_DontBlockWebhookRequestsWhilePreparing(t *testing.T) {
+	release := make(chan struct{})
+	client := httpClientFunc(func(request *http.Request) (*http.Response, error) {
+		<-release
+		return newResponse(http.StatusOK, `{"ok": true, "result": true}`), nil
+	})
+
+	bot, _ := NewBot(&Config{Token: "test", UpdateMethod: UpdateMethodWebhook, GetMeOnStart: true}, client)
+
+	started := make(chan error)
+	go func() {
+		started <- bot.Start()
+	}()
+
+	served := make(chan int)
+	go func() {
+		recorder := httptest.NewRecorder()
+		bot.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/", bytes.NewBufferString(`{"message": {}}`)))
+		served <- recorder.Code
+	}()
+
+	select {
+	case code := <-served:
+		assert.Equal(t, http.StatusServiceUnavailable, code)
+	case <-time.After(time.Second):
+		t.Fatal("webhook request blocked while the bot was starting")
+	}
+
+	close(release)
+	<-started
+	_ = bot.Stop(context.Background())
+}
+
+func TestStop_WaitForInFlightHandlers(t *testing.T) {
+	bot, _ := NewBot(&Config{Token: "test", UpdateMethod: UpdateMethodWebhook}, &mockHttpClient{})
+
+	var handled atomic.Bool
+	_ = bot.RegisterDefaultHandler(func(ctx context.Context, update *Update) error {
+		time.Sleep(100 * time.Millisecond)
+		handled.Store(true)
+		return nil
+	})
+	_ = bot.Start()
+
+	recorder := httptest.NewRecorder()
+	bot.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/", bytes.NewBufferString(`{"message": {}}`)))
+	assert.Equal(t, http.StatusOK, recorder.Code)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	err := bot.Stop(ctx)
+
+	assert.NoError(t, err)
+	assert.True(t, handled.Load())
+	assert.False(t, bot.isRunning)
+}
+
+func TestStop_CancelHandlersIfContextIsDoneBeforeTheyComplete(t *testing.T) {
+	bot, _ := NewBot(&Config{Token: "test", UpdateMethod: UpdateMethodWebhook}, &mockHttpClient{})
+
+	started := make(chan struct{})
+	cancelled := make(chan struct{})
+	_ = bot.RegisterDefaultHandler(func(ctx context.Context, update *Update) error {
+		close(started)
+		<-ctx.Done()
+		close(cancelled)
+		return nil
+	})
+	_ = bot.Start()
+
+	recorder := httptest.NewRecorder()
+	bot.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/", bytes.NewBufferString(`{"message": {}}`)))
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	err := bot.Stop(ctx)
+
+	assert.Error(t, err)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	select {
+	case <-cancelled:
+	case <-time.After(time.Second):
+		t.Fatal("handler context was not cancelled")
+	}
+}
+
+func TestStop_DontWaitForCallingHandler(t *testing.T) {
+	bot, _ := NewBot(&Config{Token: "test", UpdateMethod: UpdateMethodWebhook}, &mockHttpClient{})
+
+	stopped := make(chan error)
+	_ = bot.RegisterDefaultHandler(func(ctx context.Context, update *Update) error {
+		stopped <- bot.Stop(ctx)
+		return nil
+	})
+	_ = bot.Start()
+
+	recorder := httptest.NewRecorder()
+	bot.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/", bytes.NewBufferString(`{"message": {}}`)))
+
+	select {
+	case err := <-stopped:
+		assert.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("stopping the bot from a handler blocked")
+	}
+
+	bot.mu.RLock()
+	defer bot.mu.RUnlock()
+	assert.False(t, bot.isRunning)
+}
+
+func TestStop_DoNothingIfBotIsNotRunning(t *testing.T) {
+	bot, _ := NewBot(&Config{Token: "test", UpdateMethod: UpdateMethodWebhook}, &mockHttpClient{})
+
+	assert.NoError(t, bot.Stop(context.Background()))
+}
+
+func TestStartWithContext_StopWhenContextIsCancelled(t *testing.T) {
+	bot, _ := NewBot(&Config{Token: "test", UpdateMethod: UpdateMethodWebhook}, &mockHttpClient{})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	err := bot.StartWithContext(ctx)
+	assert.NoError(t, err)
+
+	cancel()
+
+	assert.Eventually(t, func() bool {
+		bot.mu.RLock()
+		defer bot.mu.RUnlock()
+		return !bot.isRunning
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestStart_GetMeOnStart(t *testing.T) {
+	httpClient, requests := newRecordingHttpClient(testGetMeResponse)
+	bot, _ := NewBot(&Config{Token: "test", UpdateMethod: UpdateMethodWebhook, GetMeOnStart: true}, httpClient)
+
+	err := bot.Start()
+	defer func() { _ = bot.Stop(context.Background()) }()
+
+	assert.NoError(t, err)
+	assert.Len(t, requests[endpointGetMe], 1)
+	assert.Equal(t, "fuse_bot", bot.Me().Username)
+}
+
+func TestStart_FailFastIfTokenIsInvalid(t *testing.T) {
+	httpClient := &mockHttpClient{}
+	httpClient.On("Do", mock.Anything).Return(newResponse(http.StatusUnauthorized,
+		`{"ok": false, "error_code": 401, "description": "Unauthorized"}`), nil)
+	bot, _ := NewBot(&Config{Token: "test", UpdateMethod: UpdateMethodWebhook, GetMeOnStart: true}, httpClient)
+
+	err := bot.Start()
+
+	var apiErr *APIError
+	assert.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, http.StatusUnauthorized, apiErr.ErrorCode)
+	assert.False(t, bot.isRunning)
+	assert.Nil(t, bot.Me())
+}
+
+func TestProcessUpdate_IgnoreCommandsAddressedToOtherBotsOnceUsernameIsFetched(t *testing.T) {
+	httpClient, _ := newRecordingHttpClient(testGetMeResponse)
+	bot, _ := NewBot(&Config{Token: "test"}, httpClient)
+	handled := 0
+	_ = bot.RegisterHandler("/help", func(ctx context.Context, update *Update) error {
+		handled++
+		return nil
+	})
+	_, _ = bot.GetMe(context.Background())
+
+	for _, text := range []string{"/help@other_bot", "/help@fuse_bot", "/help"} {
+		_ = bot.ProcessUpdate(context.Background(), &Update{Message: &Message{
+			Text:     text,
+			Entities: []MessageEntity{{Type: "bot_command", Length: len(text)}},
+		}})
+	}
+
+	assert.Equal(t, 2, handled)
+}
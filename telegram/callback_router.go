@@ -0,0 +1,153 @@
+package telegram // import "heytobi.dev/fuse/telegram"
+
+import (
+	"context"
+	"strings"
+	"sync/atomic"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	callbackDataSeparator = ":"
+
+	// maxCallbackDataBytes is the maximum size of the data attached to an inline keyboard button.
+	// See https://core.telegram.org/bots/api#inlinekeyboardbutton
+	maxCallbackDataBytes = 64
+)
+
+// CallbackParams holds the parameters extracted from callback data by a callback handler's template.
+type CallbackParams map[string]string
+
+// callbackRoute matches callback data against a template made of colon separated segments, e.g. vote:{poll}:{option}.
+type callbackRoute struct {
+	template string
+	segments []string
+	handler  HandlerFunc
+}
+
+// callbackAnswer tracks whether the callback query being processed has been answered.
+type callbackAnswer struct {
+	bot      *Bot
+	queryID  string
+	answered atomic.Bool
+}
+
+// RegisterCallbackHandler registers the given handler function to handle callback queries whose data matches the given
+// template. Templates are made of segments separated by colons, segments wrapped in braces are parameters matching any
+// value, e.g. vote:{poll}:{option} matches vote:12:yes. The extracted parameters are available to the handler through
+// CallbackParamsFromContext. Templates are evaluated in the order they are registered.
+//
+// Telegram shows a loading indicator on the pressed button until the callback query is answered, see ProcessUpdate.
+func (b *Bot) RegisterCallbackHandler(template string, handler HandlerFunc) error {
+	if template == "" {
+		return errEmptyCallbackTemplate
+	}
+
+	for _, route := range b.callbackRoutes {
+		if route.template == template {
+			return errCallbackHandlerExists
+		}
+	}
+
+	b.callbackRoutes = append(b.callbackRoutes, &callbackRoute{
+		template: template,
+		segments: strings.Split(template, callbackDataSeparator),
+		handler:  handler,
+	})
+
+	return nil
+}
+
+// RegisterCallbackHandler registers the given handler function to handle callback queries whose data matches the
+// given template, the handler is wrapped by the group's middleware.
+// See Bot.RegisterCallbackHandler.
+func (g *Group) RegisterCallbackHandler(template string, handler HandlerFunc) error {
+	return g.bot.RegisterCallbackHandler(template, g.wrap(handler))
+}
+
+// CallbackParamsFromContext returns the parameters extracted from the data of the callback query being processed.
+func CallbackParamsFromContext(ctx context.Context) (CallbackParams, bool) {
+	params, ok := ctx.Value(callbackParamsContextKey).(CallbackParams)
+	return params, ok
+}
+
+// AnswerCallbackQueryFromContext answers the callback query being processed, filling in the ID of the query. The
+// given answer can be nil to simply dismiss the loading indicator, e.g.
+//
+//	_, err := telegram.AnswerCallbackQueryFromContext(ctx, &telegram.AnswerCallbackQueryRequest{Text: "Saved"})
+//
+// It returns an error if the context doesn't belong to the processing of a callback query.
+func AnswerCallbackQueryFromContext(ctx context.Context, answer *AnswerCallbackQueryRequest) (bool, error) {
+	current, ok := ctx.Value(callbackAnswerContextKey).(*callbackAnswer)
+	if !ok {
+		return false, errNoCallbackQuery
+	}
+
+	request := &AnswerCallbackQueryRequest{}
+	if answer != nil {
+		*request = *answer
+	}
+	request.CallbackQueryID = current.queryID
+
+	return current.bot.AnswerCallbackQuery(ctx, request)
+}
+
+// BuildCallbackData joins the given segments into callback data matching a template, e.g. BuildCallbackData("vote",
+// "12", "yes") returns vote:12:yes. A warning is logged if the data exceeds the 64 bytes Telegram accepts.
+func BuildCallbackData(segments ...string) string {
+	data := strings.Join(segments, callbackDataSeparator)
+	if len(data) > maxCallbackDataBytes {
+		logrus.WithField("callback_data", data).Warnf(
+			"callback data is %d bytes long, Telegram rejects callback data longer than %d bytes",
+			len(data),
+			maxCallbackDataBytes,
+		)
+	}
+
+	return data
+}
+
+// matchCallbackRoute returns the first callback route matching the given data, along with the extracted parameters.
+func (b *Bot) matchCallbackRoute(data string) (*callbackRoute, CallbackParams) {
+	for _, route := range b.callbackRoutes {
+		if params, matches := route.match(data); matches {
+			return route, params
+		}
+	}
+
+	return nil, nil
+}
+
+func (r *callbackRoute) match(data string) (CallbackParams, bool) {
+	values := strings.Split(data, callbackDataSeparator)
+	if len(values) != len(r.segments) {
+		return nil, false
+	}
+
+	params := make(CallbackParams)
+	for i, segment := range r.segments {
+		if strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") {
+			params[segment[1:len(segment)-1]] = values[i]
+			continue
+		}
+
+		if segment != values[i] {
+			return nil, false
+		}
+	}
+
+	return params, true
+}
+
+// answerIfPending answers the callback query without a notification if it hasn't been answered yet.
+func (a *callbackAnswer) answerIfPending(ctx context.Context) {
+	if a.answered.Load() {
+		return
+	}
+
+	_, err := a.bot.AnswerCallbackQuery(ctx, &AnswerCallbackQueryRequest{CallbackQueryID: a.queryID})
+	if err != nil {
+		logrus.WithError(err).Error("failed to answer callback query")
+	}
+}
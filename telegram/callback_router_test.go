@@ -0,0 +1,161 @@
+package telegram
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegisterCallbackHandler_ReturnErrorIfTemplateIsInvalid(t *testing.T) {
+	bot, _ := NewBot(&Config{Token: "test"}, &mockHttpClient{})
+	handler := func(ctx context.Context, update *Update) error { return nil }
+
+	assert.Equal(t, errEmptyCallbackTemplate, bot.RegisterCallbackHandler("", handler))
+
+	_ = bot.RegisterCallbackHandler("vote:{poll}", handler)
+	assert.Equal(t, errCallbackHandlerExists, bot.RegisterCallbackHandler("vote:{poll}", handler))
+}
+
+func TestRegisterCallbackHandler_ExtractParams(t *testing.T) {
+	httpClient, _ := newRecordingHttpClient(`{"ok": true, "result": true}`)
+	bot, _ := NewBot(&Config{Token: "test"}, httpClient)
+
+	var params CallbackParams
+	_ = bot.RegisterCallbackHandler("vote:{poll}:{option}", func(ctx context.Context, update *Update) error {
+		params, _ = CallbackParamsFromContext(ctx)
+		return nil
+	})
+
+	err := bot.ProcessUpdate(context.Background(), &Update{CallbackQuery: &CallbackQuery{Data: "vote:12:yes"}})
+
+	assert.NoError(t, err)
+	assert.Equal(t, CallbackParams{"poll": "12", "option": "yes"}, params)
+}
+
+func TestRegisterCallbackHandler_FallBackToCallbackQueryHandler(t *testing.T) {
+	var handled []string
+
+	httpClient, _ := newRecordingHttpClient(`{"ok": true, "result": true}`)
+	bot, _ := NewBot(&Config{Token: "test"}, httpClient)
+	_ = bot.RegisterCallbackHandler("vote:{poll}:{option}", namedHandler("vote", &handled))
+	_ = bot.OnCallbackQuery(namedHandler("callback", &handled))
+
+	_ = bot.ProcessUpdate(context.Background(), &Update{CallbackQuery: &CallbackQuery{Data: "vote:12"}})
+	_ = bot.ProcessUpdate(context.Background(), &Update{CallbackQuery: &CallbackQuery{Data: "poll:12:yes"}})
+
+	assert.Equal(t, []string{"callback", "callback"}, handled)
+}
+
+func TestRegisterCallbackHandler_AnswerCallbackQueryIfHandlerDoesNot(t *testing.T) {
+	httpClient, requests := newRecordingHttpClient(`{"ok": true, "result": true}`)
+	bot, _ := NewBot(&Config{Token: "test"}, httpClient)
+	_ = bot.RegisterCallbackHandler("menu", func(ctx context.Context, update *Update) error { return nil })
+
+	_ = bot.ProcessUpdate(context.Background(), &Update{CallbackQuery: &CallbackQuery{ID: "query", Data: "menu"}})
+
+	assert.Len(t, requests[endpointAnswerCallbackQuery], 1)
+
+	var answer AnswerCallbackQueryRequest
+	_ = json.Unmarshal([]byte(requests[endpointAnswerCallbackQuery][0]), &answer)
+	assert.Equal(t, "query", answer.CallbackQueryID)
+}
+
+func TestRegisterCallbackHandler_DontAnswerCallbackQueryTwice(t *testing.T) {
+	httpClient, requests := newRecordingHttpClient(`{"ok": true, "result": true}`)
+	bot, _ := NewBot(&Config{Token: "test"}, httpClient)
+	_ = bot.RegisterCallbackHandler("menu", func(ctx context.Context, update *Update) error {
+		_, err := bot.AnswerCallbackQuery(ctx, &AnswerCallbackQueryRequest{
+			CallbackQueryID: update.CallbackQuery.ID,
+			Text:            "done",
+		})
+		return err
+	})
+
+	_ = bot.ProcessUpdate(context.Background(), &Update{CallbackQuery: &CallbackQuery{ID: "query", Data: "menu"}})
+
+	assert.Len(t, requests[endpointAnswerCallbackQuery], 1)
+	assert.Contains(t, requests[endpointAnswerCallbackQuery][0], `"text":"done"`)
+}
+
+func TestProcessUpdate_AnswerCallbackQueryIfMiddlewareStopsProcessing(t *testing.T) {
+	httpClient, requests := newRecordingHttpClient(`{"ok": true, "result": true}`)
+	bot, _ := NewBot(&Config{Token: "test"}, httpClient)
+	bot.Use(func(next HandlerFunc) HandlerFunc {
+		return func(ctx context.Context, update *Update) error {
+			return nil
+		}
+	})
+	_ = bot.RegisterCallbackHandler("menu", func(ctx context.Context, update *Update) error { return nil })
+
+	_ = bot.ProcessUpdate(context.Background(), &Update{CallbackQuery: &CallbackQuery{ID: "query", Data: "menu"}})
+
+	assert.Len(t, requests[endpointAnswerCallbackQuery], 1)
+}
+
+func TestProcessUpdate_AnswerCallbackQueryHandledByAnyHandler(t *testing.T) {
+	httpClient, requests := newRecordingHttpClient(`{"ok": true, "result": true}`)
+	bot, _ := NewBot(&Config{Token: "test"}, httpClient)
+	_ = bot.OnCallbackQuery(func(ctx context.Context, update *Update) error {
+		_, err := AnswerCallbackQueryFromContext(ctx, &AnswerCallbackQueryRequest{Text: "done"})
+		return err
+	})
+	_ = bot.RegisterRoute(&Route{
+		Handler: func(ctx context.Context, update *Update) error { return nil },
+		Filters: []Filter{HasPrefix("vote:")},
+	})
+
+	_ = bot.ProcessUpdate(context.Background(), &Update{CallbackQuery: &CallbackQuery{ID: "menu", Data: "menu"}})
+	_ = bot.ProcessUpdate(context.Background(), &Update{CallbackQuery: &CallbackQuery{ID: "vote", Data: "vote:1"}})
+
+	assert.Len(t, requests[endpointAnswerCallbackQuery], 2)
+	assert.JSONEq(t, `{"callback_query_id": "menu", "text": "done"}`, requests[endpointAnswerCallbackQuery][0])
+	assert.JSONEq(t, `{"callback_query_id": "vote"}`, requests[endpointAnswerCallbackQuery][1])
+}
+
+func TestAnswerCallbackQueryFromContext_AnswerQueryBeingProcessed(t *testing.T) {
+	httpClient, requests := newRecordingHttpClient(`{"ok": true, "result": true}`)
+	bot, _ := NewBot(&Config{Token: "test"}, httpClient)
+	_ = bot.RegisterCallbackHandler("menu", func(ctx context.Context, update *Update) error {
+		_, err := AnswerCallbackQueryFromContext(ctx, &AnswerCallbackQueryRequest{Text: "saved", ShowAlert: true})
+		return err
+	})
+
+	err := bot.ProcessUpdate(context.Background(), &Update{CallbackQuery: &CallbackQuery{ID: "query", Data: "menu"}})
+
+	assert.NoError(t, err)
+	assert.Len(t, requests[endpointAnswerCallbackQuery], 1)
+	assert.JSONEq(t,
+		`{"callback_query_id": "query", "text": "saved", "show_alert": true}`,
+		requests[endpointAnswerCallbackQuery][0],
+	)
+}
+
+func TestAnswerCallbackQueryFromContext_DismissLoadingIndicatorIfAnswerIsNil(t *testing.T) {
+	httpClient, requests := newRecordingHttpClient(`{"ok": true, "result": true}`)
+	bot, _ := NewBot(&Config{Token: "test"}, httpClient)
+	_ = bot.RegisterCallbackHandler("menu", func(ctx context.Context, update *Update) error {
+		_, err := AnswerCallbackQueryFromContext(ctx, nil)
+		return err
+	})
+
+	err := bot.ProcessUpdate(context.Background(), &Update{CallbackQuery: &CallbackQuery{ID: "query", Data: "menu"}})
+
+	assert.NoError(t, err)
+	assert.Len(t, requests[endpointAnswerCallbackQuery], 1)
+	assert.JSONEq(t, `{"callback_query_id": "query"}`, requests[endpointAnswerCallbackQuery][0])
+}
+
+func TestAnswerCallbackQueryFromContext_ReturnErrorOutsideOfCallbackQueries(t *testing.T) {
+	answered, err := AnswerCallbackQueryFromContext(context.Background(), &AnswerCallbackQueryRequest{Text: "saved"})
+
+	assert.False(t, answered)
+	assert.Equal(t, errNoCallbackQuery, err)
+}
+
+func TestBuildCallbackData_JoinSegments(t *testing.T) {
+	assert.Equal(t, "vote:12:yes", BuildCallbackData("vote", "12", "yes"))
+	assert.Len(t, BuildCallbackData(strings.Repeat("a", 40), strings.Repeat("b", 40)), 81)
+}
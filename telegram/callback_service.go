@@ -0,0 +1,31 @@
+package telegram // import "heytobi.dev/fuse/telegram"
+
+import (
+	"context"
+)
+
+type callbackService struct {
+	executor *executor
+}
+
+func newCallbackService(executor *executor) (*callbackService, error) {
+	return &callbackService{
+		executor: executor,
+	}, nil
+}
+
+// answerCallbackQuery sends an answer to a callback query.
+// See https://core.telegram.org/bots/api#answercallbackquery
+func (s *callbackService) answerCallbackQuery(ctx context.Context, answer *AnswerCallbackQueryRequest) (bool, error) {
+	if answer == nil {
+		return false, errNilCallbackAnswer
+	}
+
+	var answered bool
+	err := s.executor.execute(ctx, endpointAnswerCallbackQuery, answer, &answered)
+	if err != nil {
+		return false, err
+	}
+
+	return answered, nil
+}
@@ -0,0 +1,58 @@
+package telegram
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestAnswerCallbackQuery_ReturnErrorIfAnswerIsNil(t *testing.T) {
+	service, _ := newCallbackService(newExecutor(&mockHttpClient{}, testApiUrlFmt, testToken, nil))
+
+	result, err := service.answerCallbackQuery(context.Background(), nil)
+
+	assert.False(t, result)
+	assert.Equal(t, errNilCallbackAnswer, err)
+}
+
+func TestAnswerCallbackQuery_AnswerSuccessfully(t *testing.T) {
+	httpClient, requests := newRecordingHttpClient(`{"ok": true, "result": true}`)
+	service, _ := newCallbackService(newExecutor(httpClient, testApiUrlFmt, testToken, nil))
+
+	result, err := service.answerCallbackQuery(context.Background(), &AnswerCallbackQueryRequest{
+		CallbackQueryID: "query",
+		ShowAlert:       true,
+	})
+
+	assert.True(t, result)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"callback_query_id":"query","show_alert":true}`, requests[endpointAnswerCallbackQuery][0])
+}
+
+func TestAnswerCallbackQuery_ReturnErrorIfRequestFails(t *testing.T) {
+	httpClient := &mockHttpClient{}
+	httpClient.On("Do", mock.Anything).Return(nil, errors.New("error"))
+	service, _ := newCallbackService(newExecutor(httpClient, testApiUrlFmt, testToken, nil))
+
+	result, err := service.answerCallbackQuery(context.Background(), &AnswerCallbackQueryRequest{})
+
+	assert.False(t, result)
+	assert.Error(t, err)
+	assert.True(t, strings.EqualFold(err.Error(), "answerCallbackQuery request failed: error"))
+}
+
+func TestAnswerCallbackQuery_ReturnErrorIfResponseCodeIsNot200(t *testing.T) {
+	httpClient := &mockHttpClient{}
+	httpClient.On("Do", mock.Anything).Return(newResponse(http.StatusBadRequest, ""), nil)
+	service, _ := newCallbackService(newExecutor(httpClient, testApiUrlFmt, testToken, nil))
+
+	result, err := service.answerCallbackQuery(context.Background(), &AnswerCallbackQueryRequest{})
+
+	assert.False(t, result)
+	assert.Error(t, err)
+}
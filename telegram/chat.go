@@ -1,5 +1,14 @@
 package telegram // import "heytobi.dev/fuse/telegram"
 
+// Chat types.
+// See https://core.telegram.org/bots/api#chat
+const (
+	ChatTypePrivate    = "private"
+	ChatTypeGroup      = "group"
+	ChatTypeSupergroup = "supergroup"
+	ChatTypeChannel    = "channel"
+)
+
 // ChatPhoto represents a chat photo.
 // See https://core.telegram.org/bots/api#chatphoto
 type ChatPhoto struct {
@@ -0,0 +1,94 @@
+package telegram // import "heytobi.dev/fuse/telegram"
+
+import (
+	"context"
+	"strings"
+	"unicode/utf16"
+)
+
+const (
+	entityTypeBotCommand = "bot_command"
+
+	commandStart = "/start"
+)
+
+// Command represents a bot command parsed from a message, e.g. /remind@MyBot 5m stretch.
+// See https://core.telegram.org/bots/features#commands
+type Command struct {
+	// Name is the command including the leading slash, without the bot mention, e.g. /remind.
+	Name string
+	// Mention is the username of the bot the command was addressed to, without the @, if any.
+	Mention string
+	// RawArgs is the text following the command, with surrounding whitespace trimmed.
+	RawArgs string
+	// Args are the whitespace separated arguments following the command.
+	Args []string
+}
+
+// DeepLinkPayload returns the payload of a deep link, i.e. the parameter passed to a /start command when a user opens
+// a t.me/<bot_username>?start=<payload> link. It returns an empty string for any other command.
+// See https://core.telegram.org/bots/features#deep-linking
+func (c *Command) DeepLinkPayload() string {
+	if c.Name != commandStart {
+		return ""
+	}
+
+	return c.RawArgs
+}
+
+// CommandFromContext returns the command parsed from the message being processed, if the message starts with one.
+func CommandFromContext(ctx context.Context) (*Command, bool) {
+	command, ok := ctx.Value(commandContextKey).(*Command)
+	return command, ok
+}
+
+// parseCommand parses the command at the start of the given message, as identified by a bot_command entity. It returns
+// nil if the message doesn't start with a command.
+func parseCommand(message *Message) *Command {
+	text := message.Text
+	commandLength := -1
+
+	for _, entity := range message.Entities {
+		if entity.Type == entityTypeBotCommand && entity.Offset == 0 {
+			commandLength = len(entityText(text, entity))
+			break
+		}
+	}
+
+	// messages built by hand rather than received from Telegram might not have entities.
+	if commandLength == -1 && len(message.Entities) == 0 && strings.HasPrefix(text, "/") {
+		commandLength = strings.IndexFunc(text, isCommandSeparator)
+		if commandLength == -1 {
+			commandLength = len(text)
+		}
+	}
+
+	if commandLength <= 1 {
+		return nil
+	}
+
+	name, mention, _ := strings.Cut(text[:commandLength], "@")
+	rawArgs := strings.TrimSpace(text[commandLength:])
+
+	return &Command{
+		Name:    name,
+		Mention: mention,
+		RawArgs: rawArgs,
+		Args:    strings.Fields(rawArgs),
+	}
+}
+
+// entityText returns the part of the given text covered by the given entity. Entity offsets and lengths are measured
+// in UTF-16 code units.
+func entityText(text string, entity MessageEntity) string {
+	encoded := utf16.Encode([]rune(text))
+	if entity.Offset < 0 || entity.Length < 0 || entity.Offset+entity.Length > len(encoded) {
+		return ""
+	}
+
+	return string(utf16.Decode(encoded[entity.Offset : entity.Offset+entity.Length]))
+}
+
+func isCommandSeparator(r rune) bool {
+	return r == ' ' || r == '\n' || r == '\t'
+}
@@ -0,0 +1,67 @@
+package telegram // import "heytobi.dev/fuse/telegram"
+
+import (
+	"context"
+)
+
+type commandService struct {
+	executor *executor
+}
+
+func newCommandService(executor *executor) (*commandService, error) {
+	return &commandService{
+		executor: executor,
+	}, nil
+}
+
+// setMyCommands sets the list of commands shown in the menu of the bot.
+// See https://core.telegram.org/bots/api#setmycommands
+func (s *commandService) setMyCommands(ctx context.Context, request *SetMyCommandsRequest) (bool, error) {
+	if request == nil {
+		return false, errNilCommandsRequest
+	}
+
+	if len(request.Commands) > maxBotCommands {
+		return false, errTooManyCommands
+	}
+
+	var result bool
+	err := s.executor.execute(ctx, endpointSetMyCommands, request, &result)
+	if err != nil {
+		return false, err
+	}
+
+	return result, nil
+}
+
+// getMyCommands gets the list of commands shown in the menu of the bot.
+// See https://core.telegram.org/bots/api#getmycommands
+func (s *commandService) getMyCommands(ctx context.Context, request *GetMyCommandsRequest) ([]BotCommand, error) {
+	if request == nil {
+		request = &GetMyCommandsRequest{}
+	}
+
+	var commands []BotCommand
+	err := s.executor.execute(ctx, endpointGetMyCommands, request, &commands)
+	if err != nil {
+		return nil, err
+	}
+
+	return commands, nil
+}
+
+// deleteMyCommands deletes the list of commands shown in the menu of the bot.
+// See https://core.telegram.org/bots/api#deletemycommands
+func (s *commandService) deleteMyCommands(ctx context.Context, request *DeleteMyCommandsRequest) (bool, error) {
+	if request == nil {
+		request = &DeleteMyCommandsRequest{}
+	}
+
+	var result bool
+	err := s.executor.execute(ctx, endpointDeleteMyCommands, request, &result)
+	if err != nil {
+		return false, err
+	}
+
+	return result, nil
+}
@@ -0,0 +1,72 @@
+package telegram
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetMyCommands_SendScopeAndLanguage(t *testing.T) {
+	httpClient, requests := newRecordingHttpClient(`{"ok": true, "result": true}`)
+	service, _ := newCommandService(newExecutor(httpClient, testApiUrlFmt, testToken, nil))
+
+	result, err := service.setMyCommands(context.Background(), &SetMyCommandsRequest{
+		Commands:     []BotCommand{{Command: "start", Description: "Démarrer"}},
+		Scope:        &BotCommandScopeAllPrivateChats{},
+		LanguageCode: "fr",
+	})
+
+	assert.True(t, result)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{
+		"commands": [{"command": "start", "description": "Démarrer"}],
+		"scope": {"type": "all_private_chats"},
+		"language_code": "fr"
+	}`, requests[endpointSetMyCommands][0])
+}
+
+func TestSetMyCommands_ReturnErrorIfThereAreTooManyCommands(t *testing.T) {
+	service, _ := newCommandService(newExecutor(&mockHttpClient{}, testApiUrlFmt, testToken, nil))
+
+	_, err := service.setMyCommands(context.Background(), &SetMyCommandsRequest{Commands: make([]BotCommand, 101)})
+
+	assert.Equal(t, errTooManyCommands, err)
+}
+
+func TestSetMyCommands_ReturnErrorIfRequestIsNil(t *testing.T) {
+	service, _ := newCommandService(newExecutor(&mockHttpClient{}, testApiUrlFmt, testToken, nil))
+
+	_, err := service.setMyCommands(context.Background(), nil)
+
+	assert.Equal(t, errNilCommandsRequest, err)
+}
+
+func TestGetMyCommands_ReturnCommands(t *testing.T) {
+	httpClient, requests := newRecordingHttpClient(
+		`{"ok": true, "result": [{"command": "start", "description": "Start"}]}`,
+	)
+	service, _ := newCommandService(newExecutor(httpClient, testApiUrlFmt, testToken, nil))
+
+	commands, err := service.getMyCommands(context.Background(), &GetMyCommandsRequest{
+		Scope: &BotCommandScopeChatMember{ChatID: 7, UserID: 9},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []BotCommand{{Command: "start", Description: "Start"}}, commands)
+	assert.JSONEq(t,
+		`{"scope": {"type": "chat_member", "chat_id": 7, "user_id": 9}}`,
+		requests[endpointGetMyCommands][0],
+	)
+}
+
+func TestDeleteMyCommands_DeleteDefaultCommandsIfRequestIsNil(t *testing.T) {
+	httpClient, requests := newRecordingHttpClient(`{"ok": true, "result": true}`)
+	service, _ := newCommandService(newExecutor(httpClient, testApiUrlFmt, testToken, nil))
+
+	result, err := service.deleteMyCommands(context.Background(), nil)
+
+	assert.True(t, result)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{}`, requests[endpointDeleteMyCommands][0])
+}
@@ -0,0 +1,111 @@
+package telegram
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newCommandMessage(text string, commandLength int) *Message {
+	return &Message{
+		Text:     text,
+		Entities: []MessageEntity{{Type: entityTypeBotCommand, Offset: 0, Length: commandLength}},
+	}
+}
+
+func TestParseCommand_ParseCommandWithArguments(t *testing.T) {
+	command := parseCommand(newCommandMessage("/remind 5m  stretch", 7))
+
+	assert.Equal(t, "/remind", command.Name)
+	assert.Equal(t, "", command.Mention)
+	assert.Equal(t, "5m  stretch", command.RawArgs)
+	assert.Equal(t, []string{"5m", "stretch"}, command.Args)
+}
+
+func TestParseCommand_ParseBotMention(t *testing.T) {
+	command := parseCommand(newCommandMessage("/help@OurBot", 12))
+
+	assert.Equal(t, "/help", command.Name)
+	assert.Equal(t, "OurBot", command.Mention)
+	assert.Empty(t, command.Args)
+}
+
+func TestParseCommand_ReturnNilIfMessageDoesNotStartWithCommand(t *testing.T) {
+	message := &Message{
+		Text:     "please /help",
+		Entities: []MessageEntity{{Type: entityTypeBotCommand, Offset: 7, Length: 5}},
+	}
+
+	assert.Nil(t, parseCommand(message))
+	assert.Nil(t, parseCommand(&Message{Text: "hello"}))
+}
+
+func TestParseCommand_ParseMessagesWithoutEntities(t *testing.T) {
+	command := parseCommand(&Message{Text: "/start abc"})
+
+	assert.Equal(t, "/start", command.Name)
+	assert.Equal(t, "abc", command.DeepLinkPayload())
+}
+
+func TestParseCommand_MeasureEntitiesInUTF16CodeUnits(t *testing.T) {
+	command := parseCommand(newCommandMessage("/say 👋 hi", 4))
+
+	assert.Equal(t, "/say", command.Name)
+	assert.Equal(t, []string{"👋", "hi"}, command.Args)
+}
+
+func TestDeepLinkPayload_ReturnEmptyPayloadForOtherCommands(t *testing.T) {
+	command := parseCommand(newCommandMessage("/help abc", 5))
+
+	assert.Equal(t, "", command.DeepLinkPayload())
+}
+
+func TestProcessUpdate_RouteCommandsWithArgumentsAndMentions(t *testing.T) {
+	bot, _ := NewBot(&Config{Token: "test", Username: "OurBot"}, &mockHttpClient{})
+
+	var handled []*Command
+	_ = bot.RegisterHandler("/start", func(ctx context.Context, update *Update) error {
+		command, _ := CommandFromContext(ctx)
+		handled = append(handled, command)
+		return nil
+	})
+
+	_ = bot.ProcessUpdate(context.Background(), &Update{Message: newCommandMessage("/start abc", 6)})
+	_ = bot.ProcessUpdate(context.Background(), &Update{Message: newCommandMessage("/start@ourbot", 13)})
+
+	assert.Len(t, handled, 2)
+	assert.Equal(t, "abc", handled[0].DeepLinkPayload())
+	assert.Equal(t, "ourbot", handled[1].Mention)
+}
+
+func TestProcessUpdate_IgnoreCommandsAddressedToOtherBots(t *testing.T) {
+	bot, _ := NewBot(&Config{Token: "test", Username: "OurBot"}, &mockHttpClient{})
+
+	handled := false
+	handler := func(ctx context.Context, update *Update) error {
+		handled = true
+		return nil
+	}
+	_ = bot.RegisterHandler("/help", handler)
+	_ = bot.RegisterDefaultHandler(handler)
+
+	err := bot.ProcessUpdate(context.Background(), &Update{Message: newCommandMessage("/help@OtherBot", 14)})
+
+	assert.NoError(t, err)
+	assert.False(t, handled)
+}
+
+func TestProcessUpdate_PassUnknownCommandsToDefaultHandler(t *testing.T) {
+	bot, _ := NewBot(&Config{Token: "test"}, &mockHttpClient{})
+
+	var command *Command
+	_ = bot.RegisterDefaultHandler(func(ctx context.Context, update *Update) error {
+		command, _ = CommandFromContext(ctx)
+		return nil
+	})
+
+	_ = bot.ProcessUpdate(context.Background(), &Update{Message: newCommandMessage("/unknown arg", 8)})
+
+	assert.Equal(t, "/unknown", command.Name)
+}
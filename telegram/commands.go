@@ -0,0 +1,215 @@
+package telegram // import "heytobi.dev/fuse/telegram"
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	botCommandScopeTypeDefault               = "default"
+	botCommandScopeTypeAllPrivateChats       = "all_private_chats"
+	botCommandScopeTypeAllGroupChats         = "all_group_chats"
+	botCommandScopeTypeAllChatAdministrators = "all_chat_administrators"
+	botCommandScopeTypeChat                  = "chat"
+	botCommandScopeTypeChatAdministrators    = "chat_administrators"
+	botCommandScopeTypeChatMember            = "chat_member"
+)
+
+// maxBotCommands is the maximum number of commands of a bot command menu.
+// See https://core.telegram.org/bots/api#setmycommands
+const maxBotCommands = 100
+
+// maxCommandDescriptionLength is the maximum length of the description of a command, in characters.
+// See https://core.telegram.org/bots/api#botcommand
+const maxCommandDescriptionLength = 256
+
+// commandNamePattern matches the names Telegram accepts for commands shown in the command menu.
+// See https://core.telegram.org/bots/api#botcommand
+var commandNamePattern = regexp.MustCompile(`^[a-z0-9_]{1,32}$`)
+
+// BotCommandScope defines the users for which a list of bot commands is shown, e.g. BotCommandScopeAllPrivateChats.
+// See https://core.telegram.org/bots/api#botcommandscope
+type BotCommandScope interface {
+	scopeType() string
+}
+
+// BotCommandScopeDefault is the scope of the commands shown to users for which no narrower scope has commands.
+// See https://core.telegram.org/bots/api#botcommandscopedefault
+type BotCommandScopeDefault struct{}
+
+// BotCommandScopeAllPrivateChats is the scope of the commands shown in all private chats.
+// See https://core.telegram.org/bots/api#botcommandscopeallprivatechats
+type BotCommandScopeAllPrivateChats struct{}
+
+// BotCommandScopeAllGroupChats is the scope of the commands shown in all group and supergroup chats.
+// See https://core.telegram.org/bots/api#botcommandscopeallgroupchats
+type BotCommandScopeAllGroupChats struct{}
+
+// BotCommandScopeAllChatAdministrators is the scope of the commands shown to the administrators of all group and
+// supergroup chats.
+// See https://core.telegram.org/bots/api#botcommandscopeallchatadministrators
+type BotCommandScopeAllChatAdministrators struct{}
+
+// BotCommandScopeChat is the scope of the commands shown in a specific chat.
+// See https://core.telegram.org/bots/api#botcommandscopechat
+type BotCommandScopeChat struct {
+	ChatID int64 `json:"chat_id"`
+}
+
+// BotCommandScopeChatAdministrators is the scope of the commands shown to the administrators of a specific group or
+// supergroup chat.
+// See https://core.telegram.org/bots/api#botcommandscopechatadministrators
+type BotCommandScopeChatAdministrators struct {
+	ChatID int64 `json:"chat_id"`
+}
+
+// BotCommandScopeChatMember is the scope of the commands shown to a specific member of a group or supergroup chat.
+// See https://core.telegram.org/bots/api#botcommandscopechatmember
+type BotCommandScopeChatMember struct {
+	ChatID int64 `json:"chat_id"`
+	UserID int64 `json:"user_id"`
+}
+
+func (s *BotCommandScopeDefault) scopeType() string {
+	return botCommandScopeTypeDefault
+}
+
+func (s *BotCommandScopeAllPrivateChats) scopeType() string {
+	return botCommandScopeTypeAllPrivateChats
+}
+
+func (s *BotCommandScopeAllGroupChats) scopeType() string {
+	return botCommandScopeTypeAllGroupChats
+}
+
+func (s *BotCommandScopeAllChatAdministrators) scopeType() string {
+	return botCommandScopeTypeAllChatAdministrators
+}
+
+func (s *BotCommandScopeChat) scopeType() string {
+	return botCommandScopeTypeChat
+}
+
+func (s *BotCommandScopeChatAdministrators) scopeType() string {
+	return botCommandScopeTypeChatAdministrators
+}
+
+func (s *BotCommandScopeChatMember) scopeType() string {
+	return botCommandScopeTypeChatMember
+}
+
+// MarshalJSON adds the type of the scope, as expected by the Bot API.
+func (s *BotCommandScopeDefault) MarshalJSON() ([]byte, error) {
+	type scope BotCommandScopeDefault
+	return marshalWithType(s.scopeType(), (*scope)(s))
+}
+
+// MarshalJSON adds the type of the scope, as expected by the Bot API.
+func (s *BotCommandScopeAllPrivateChats) MarshalJSON() ([]byte, error) {
+	type scope BotCommandScopeAllPrivateChats
+	return marshalWithType(s.scopeType(), (*scope)(s))
+}
+
+// MarshalJSON adds the type of the scope, as expected by the Bot API.
+func (s *BotCommandScopeAllGroupChats) MarshalJSON() ([]byte, error) {
+	type scope BotCommandScopeAllGroupChats
+	return marshalWithType(s.scopeType(), (*scope)(s))
+}
+
+// MarshalJSON adds the type of the scope, as expected by the Bot API.
+func (s *BotCommandScopeAllChatAdministrators) MarshalJSON() ([]byte, error) {
+	type scope BotCommandScopeAllChatAdministrators
+	return marshalWithType(s.scopeType(), (*scope)(s))
+}
+
+// MarshalJSON adds the type of the scope, as expected by the Bot API.
+func (s *BotCommandScopeChat) MarshalJSON() ([]byte, error) {
+	type scope BotCommandScopeChat
+	return marshalWithType(s.scopeType(), (*scope)(s))
+}
+
+// MarshalJSON adds the type of the scope, as expected by the Bot API.
+func (s *BotCommandScopeChatAdministrators) MarshalJSON() ([]byte, error) {
+	type scope BotCommandScopeChatAdministrators
+	return marshalWithType(s.scopeType(), (*scope)(s))
+}
+
+// MarshalJSON adds the type of the scope, as expected by the Bot API.
+func (s *BotCommandScopeChatMember) MarshalJSON() ([]byte, error) {
+	type scope BotCommandScopeChatMember
+	return marshalWithType(s.scopeType(), (*scope)(s))
+}
+
+// SetMyCommandsRequest defines the list of commands shown in the menu of the bot to the users of the given scope and
+// language. Commands are shown to all users if no scope is given, and to users whose language has no dedicated
+// commands if no language code is given.
+// See https://core.telegram.org/bots/api#setmycommands
+type SetMyCommandsRequest struct {
+	Commands     []BotCommand    `json:"commands"`
+	Scope        BotCommandScope `json:"scope,omitempty"`
+	LanguageCode string          `json:"language_code,omitempty"`
+}
+
+// GetMyCommandsRequest identifies the list of commands to get by its scope and language.
+// See https://core.telegram.org/bots/api#getmycommands
+type GetMyCommandsRequest struct {
+	Scope        BotCommandScope `json:"scope,omitempty"`
+	LanguageCode string          `json:"language_code,omitempty"`
+}
+
+// DeleteMyCommandsRequest identifies the list of commands to delete by its scope and language, after which the
+// commands of a broader scope are shown.
+// See https://core.telegram.org/bots/api#deletemycommands
+type DeleteMyCommandsRequest struct {
+	Scope        BotCommandScope `json:"scope,omitempty"`
+	LanguageCode string          `json:"language_code,omitempty"`
+}
+
+// RegisterCommand registers the given handler function to handle invocations of the given command, e.g. /start, like
+// RegisterHandler. If PublishCommands is enabled in the config, the command and its description are added to the
+// command menu of the bot when it starts, in the order commands are registered.
+//
+// The leading slash of the command is optional, the handler is registered for the command with it either way. Since
+// Telegram only accepts such commands in the command menu, the command, without its leading slash, must be 1 to 32
+// characters long and only contain lowercase English letters, digits and underscores, and its description must be 1
+// to 256 characters long.
+func (b *Bot) RegisterCommand(command string, description string, handler HandlerFunc) error {
+	name := strings.TrimPrefix(command, "/")
+	if !commandNamePattern.MatchString(name) {
+		return errInvalidCommandName
+	}
+
+	if length := utf8.RuneCountInString(description); length == 0 || length > maxCommandDescriptionLength {
+		return errInvalidDescription
+	}
+
+	err := b.RegisterHandler("/"+name, handler)
+	if err != nil {
+		return err
+	}
+
+	b.commands = append(b.commands, BotCommand{
+		Command:     name,
+		Description: description,
+	})
+
+	return nil
+}
+
+// RegisterCommand registers the given handler function to handle invocations of the given command, the handler is
+// wrapped by the group's middleware.
+// See Bot.RegisterCommand.
+func (g *Group) RegisterCommand(command string, description string, handler HandlerFunc) error {
+	return g.bot.RegisterCommand(command, description, g.wrap(handler))
+}
+
+// publishCommands replaces the commands shown in the menu of the bot by the commands registered through
+// RegisterCommand.
+func (b *Bot) publishCommands(ctx context.Context) error {
+	// an empty list, rather than a null one, clears the menu if no command was registered.
+	commands := append([]BotCommand{}, b.commands...)
+	_, err := b.commandService.setMyCommands(ctx, &SetMyCommandsRequest{Commands: commands})
+	return err
+}
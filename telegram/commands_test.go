@@ -0,0 +1,137 @@
+package telegram
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestBotCommandScope_MarshalWithType(t *testing.T) {
+	scopes := map[BotCommandScope]string{
+		&BotCommandScopeDefault{}:                        `{"type": "default"}`,
+		&BotCommandScopeAllPrivateChats{}:                `{"type": "all_private_chats"}`,
+		&BotCommandScopeAllGroupChats{}:                  `{"type": "all_group_chats"}`,
+		&BotCommandScopeAllChatAdministrators{}:          `{"type": "all_chat_administrators"}`,
+		&BotCommandScopeChat{ChatID: 7}:                  `{"type": "chat", "chat_id": 7}`,
+		&BotCommandScopeChatAdministrators{ChatID: 7}:    `{"type": "chat_administrators", "chat_id": 7}`,
+		&BotCommandScopeChatMember{ChatID: 7, UserID: 9}: `{"type": "chat_member", "chat_id": 7, "user_id": 9}`,
+	}
+
+	for scope, expected := range scopes {
+		scopeJson, err := json.Marshal(scope)
+
+		assert.NoError(t, err)
+		assert.JSONEq(t, expected, string(scopeJson))
+	}
+}
+
+func TestRegisterCommand_RegisterHandler(t *testing.T) {
+	handled := false
+	bot, _ := NewBot(&Config{Token: "test"}, &mockHttpClient{})
+	_ = bot.RegisterCommand("/start", "Start the bot", func(ctx context.Context, update *Update) error {
+		handled = true
+		return nil
+	})
+
+	_ = bot.ProcessUpdate(context.Background(), &Update{Message: &Message{
+		Text:     "/start",
+		Entities: []MessageEntity{{Type: "bot_command", Length: 6}},
+	}})
+
+	assert.True(t, handled)
+	assert.Equal(t, []BotCommand{{Command: "start", Description: "Start the bot"}}, bot.commands)
+}
+
+func TestRegisterCommand_ReturnErrorIfHandlerExists(t *testing.T) {
+	bot, _ := NewBot(&Config{Token: "test"}, &mockHttpClient{})
+	_ = bot.RegisterHandler("/start", func(ctx context.Context, update *Update) error { return nil })
+
+	err := bot.RegisterCommand("/start", "Start the bot", func(ctx context.Context, update *Update) error {
+		return nil
+	})
+
+	assert.Equal(t, errHandlerExists, err)
+	assert.Empty(t, bot.commands)
+}
+
+func TestRegisterCommand_ReturnErrorIfCommandIsInvalid(t *testing.T) {
+	bot, _ := NewBot(&Config{Token: "test"}, &mockHttpClient{})
+	handler := func(ctx context.Context, update *Update) error { return nil }
+
+	for _, command := range []string{"", "/", "/Start", "/start-now", "/démarrer", "/" + strings.Repeat("a", 33)} {
+		assert.Equal(t, errInvalidCommandName, bot.RegisterCommand(command, "Start the bot", handler), command)
+	}
+
+	assert.Equal(t, errInvalidDescription, bot.RegisterCommand("/start", "", handler))
+	assert.Equal(t, errInvalidDescription, bot.RegisterCommand("/start", strings.Repeat("é", 257), handler))
+	assert.Empty(t, bot.commands)
+	assert.Empty(t, bot.handlers)
+
+	assert.NoError(t, bot.RegisterCommand("/"+strings.Repeat("a", 32), strings.Repeat("é", 256), handler))
+	assert.NoError(t, bot.RegisterCommand("/set_language2", "Set the language", handler))
+}
+
+func TestRegisterCommand_RegisterCommandWithoutLeadingSlash(t *testing.T) {
+	handled := false
+	bot, _ := NewBot(&Config{Token: "test"}, &mockHttpClient{})
+
+	err := bot.RegisterCommand("help", "Show help", func(ctx context.Context, update *Update) error {
+		handled = true
+		return nil
+	})
+	assert.NoError(t, err)
+	err = bot.RegisterCommand("/help", "Show help", func(ctx context.Context, update *Update) error { return nil })
+	assert.Equal(t, errHandlerExists, err)
+
+	err = bot.ProcessUpdate(context.Background(), &Update{Message: &Message{Text: "/help"}})
+
+	assert.NoError(t, err)
+	assert.True(t, handled)
+	assert.Equal(t, []BotCommand{{Command: "help", Description: "Show help"}}, bot.commands)
+}
+
+func TestStart_PublishRegisteredCommands(t *testing.T) {
+	httpClient, requests := newRecordingHttpClient(`{"ok": true, "result": true}`)
+	bot, _ := NewBot(&Config{Token: "test", UpdateMethod: UpdateMethodWebhook, PublishCommands: true}, httpClient)
+	_ = bot.RegisterCommand("/start", "Start the bot", func(ctx context.Context, update *Update) error { return nil })
+	_ = bot.RegisterCommand("/help", "Show help", func(ctx context.Context, update *Update) error { return nil })
+
+	err := bot.Start()
+	defer func() { _ = bot.Stop(context.Background()) }()
+
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"commands": [
+		{"command": "start", "description": "Start the bot"},
+		{"command": "help", "description": "Show help"}
+	]}`, requests[endpointSetMyCommands][0])
+}
+
+func TestStart_DontPublishCommandsByDefault(t *testing.T) {
+	httpClient, requests := newRecordingHttpClient(`{"ok": true, "result": true}`)
+	bot, _ := NewBot(&Config{Token: "test", UpdateMethod: UpdateMethodWebhook}, httpClient)
+	_ = bot.RegisterCommand("/start", "Start the bot", func(ctx context.Context, update *Update) error { return nil })
+
+	err := bot.Start()
+	defer func() { _ = bot.Stop(context.Background()) }()
+
+	assert.NoError(t, err)
+	assert.Empty(t, requests[endpointSetMyCommands])
+}
+
+func TestStart_ReturnErrorIfCommandsCannotBePublished(t *testing.T) {
+	httpClient := &mockHttpClient{}
+	httpClient.On("Do", mock.Anything).Return(newResponse(http.StatusBadRequest,
+		`{"ok": false, "error_code": 400, "description": "Bad Request: command is invalid"}`), nil)
+	bot, _ := NewBot(&Config{Token: "test", UpdateMethod: UpdateMethodWebhook, PublishCommands: true}, httpClient)
+	_ = bot.RegisterCommand("/Start", "Start the bot", func(ctx context.Context, update *Update) error { return nil })
+
+	err := bot.Start()
+
+	assert.Error(t, err)
+	assert.False(t, bot.isRunning)
+}
@@ -0,0 +1,12 @@
+package telegram // import "heytobi.dev/fuse/telegram"
+
+// contextKey is the type of the keys of the values fuse attaches to the context passed to handlers.
+type contextKey int
+
+const (
+	commandContextKey contextKey = iota
+	callbackParamsContextKey
+	callbackAnswerContextKey
+	handlingBotContextKey
+	acknowledgementContextKey
+)
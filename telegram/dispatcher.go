@@ -0,0 +1,95 @@
+package telegram // import "heytobi.dev/fuse/telegram"
+
+import (
+	"sync"
+)
+
+const (
+	// OrderByChat processes updates from the same chat sequentially.
+	OrderByChat = "chat"
+	// OrderByUser processes updates from the same user sequentially.
+	OrderByUser = "user"
+
+	defaultWorkers         = 1
+	defaultWorkerQueueSize = 100
+)
+
+// dispatcher distributes updates across a pool of workers. Updates sharing an ordering key, i.e. the same chat or the
+// same user, are always routed to the same worker so that they are processed sequentially, in the order they were
+// received, while updates with different keys can be processed in parallel.
+// Each worker has a bounded queue, once it is full, the dispatcher stops consuming updates until there is room again.
+type dispatcher struct {
+	queues  []chan *Update
+	orderBy string
+	process func(update *Update)
+}
+
+func newDispatcher(workers, queueSize int, orderBy string, process func(update *Update)) *dispatcher {
+	if workers < 1 {
+		workers = defaultWorkers
+	}
+
+	if queueSize < 1 {
+		queueSize = defaultWorkerQueueSize
+	}
+
+	queues := make([]chan *Update, workers)
+	for i := range queues {
+		queues[i] = make(chan *Update, queueSize)
+	}
+
+	return &dispatcher{
+		queues:  queues,
+		orderBy: orderBy,
+		process: process,
+	}
+}
+
+// run distributes every update received on the given channel to the workers. It returns once the channel is closed
+// and every update has been processed.
+func (d *dispatcher) run(updates <-chan *Update) {
+	var workers sync.WaitGroup
+	for _, queue := range d.queues {
+		workers.Add(1)
+		go func(queue <-chan *Update) {
+			defer workers.Done()
+			for update := range queue {
+				d.process(update)
+			}
+		}(queue)
+	}
+
+	for update := range updates {
+		d.queues[d.workerFor(update)] <- update
+	}
+
+	for _, queue := range d.queues {
+		close(queue)
+	}
+
+	workers.Wait()
+}
+
+func (d *dispatcher) workerFor(update *Update) int {
+	return int(uint64(d.orderingKey(update)) % uint64(len(d.queues)))
+}
+
+// orderingKey returns the ID of the chat or user the update belongs to, depending on how updates are ordered. Updates
+// that belong to neither are keyed by their own ID.
+func (d *dispatcher) orderingKey(update *Update) int64 {
+	chat, sender := update.Chat(), update.Sender()
+
+	if d.orderBy == OrderByUser && sender != nil {
+		return sender.ID
+	}
+
+	if chat != nil {
+		return chat.ID
+	}
+
+	if sender != nil {
+		return sender.ID
+	}
+
+	return int64(update.ID)
+}
@@ -0,0 +1,103 @@
+package telegram
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDispatcher_ProcessUpdatesFromTheSameChatInOrder(t *testing.T) {
+	var mu sync.Mutex
+	processed := make(map[int64][]int)
+
+	d := newDispatcher(4, 10, OrderByChat, func(update *Update) {
+		time.Sleep(time.Millisecond)
+
+		mu.Lock()
+		defer mu.Unlock()
+		processed[update.Message.Chat.ID] = append(processed[update.Message.Chat.ID], update.ID)
+	})
+
+	updates := make(chan *Update)
+	go func() {
+		defer close(updates)
+		for id := 1; id <= 40; id++ {
+			updates <- &Update{ID: id, Message: &Message{Chat: &Chat{ID: int64(id % 5)}}}
+		}
+	}()
+
+	d.run(updates)
+
+	for chatID, ids := range processed {
+		assert.Len(t, ids, 8)
+		for i := 1; i < len(ids); i++ {
+			assert.Less(t, ids[i-1], ids[i], "updates for chat %d were processed out of order", chatID)
+		}
+	}
+}
+
+func TestDispatcher_ProcessUpdatesFromDifferentChatsInParallel(t *testing.T) {
+	secondProcessed := make(chan struct{})
+
+	d := newDispatcher(2, 10, OrderByChat, func(update *Update) {
+		if update.ID == 1 {
+			select {
+			case <-secondProcessed:
+			case <-time.After(time.Second):
+				t.Error("slow chat blocked other chats")
+			}
+			return
+		}
+		close(secondProcessed)
+	})
+
+	updates := make(chan *Update, 2)
+	updates <- &Update{ID: 1, Message: &Message{Chat: &Chat{ID: 1}}}
+	updates <- &Update{ID: 2, Message: &Message{Chat: &Chat{ID: 2}}}
+	close(updates)
+
+	d.run(updates)
+}
+
+func TestDispatcher_OrderByUser(t *testing.T) {
+	d := newDispatcher(4, 10, OrderByUser, func(update *Update) {})
+
+	fromUser := &Update{Message: &Message{Chat: &Chat{ID: -100}, Sender: &User{ID: 7}}}
+	callback := &Update{CallbackQuery: &CallbackQuery{From: &User{ID: 7}}}
+
+	assert.Equal(t, int64(7), d.orderingKey(fromUser))
+	assert.Equal(t, d.workerFor(fromUser), d.workerFor(callback))
+}
+
+func TestDispatcher_OrderByChat(t *testing.T) {
+	d := newDispatcher(4, 10, OrderByChat, func(update *Update) {})
+
+	assert.Equal(t, int64(-100), d.orderingKey(&Update{Message: &Message{Chat: &Chat{ID: -100}, Sender: &User{ID: 7}}}))
+	assert.Equal(t, int64(7), d.orderingKey(&Update{InlineQuery: &InlineQuery{Sender: &User{ID: 7}}}))
+	assert.Equal(t, int64(3), d.orderingKey(&Update{ID: 3, Poll: &Poll{}}))
+}
+
+func TestDispatcher_ApplyBackpressureWhenQueuesAreFull(t *testing.T) {
+	release := make(chan struct{})
+	d := newDispatcher(1, 1, OrderByChat, func(update *Update) { <-release })
+
+	updates := make(chan *Update)
+	go d.run(updates)
+
+	// one update is being processed, one is queued and one is waiting for room in the queue, the next one can't be
+	// accepted.
+	updates <- &Update{ID: 1}
+	updates <- &Update{ID: 2}
+	updates <- &Update{ID: 3}
+
+	select {
+	case updates <- &Update{ID: 4}:
+		t.Fatal("update was accepted while the worker queue was full")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(release)
+	close(updates)
+}
@@ -0,0 +1,136 @@
+package telegram // import "heytobi.dev/fuse/telegram"
+
+import (
+	"encoding/json"
+)
+
+// EditMessageTextRequest defines new text for a message. The message is identified either by ChatID and MessageID,
+// or by InlineMessageID for messages sent through inline queries.
+// See https://core.telegram.org/bots/api#editmessagetext
+type EditMessageTextRequest struct {
+	ChatID                int64                 `json:"chat_id,omitempty"`
+	MessageID             int64                 `json:"message_id,omitempty"`
+	InlineMessageID       string                `json:"inline_message_id,omitempty"`
+	Text                  string                `json:"text"`
+	ParseMode             string                `json:"parse_mode,omitempty"`
+	Entities              []MessageEntity       `json:"entities,omitempty"`
+	DisableWebPagePreview bool                  `json:"disable_web_page_preview,omitempty"`
+	ReplyMarkup           *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
+}
+
+// EditMessageCaptionRequest defines a new caption for a message. The message is identified either by ChatID and
+// MessageID, or by InlineMessageID for messages sent through inline queries.
+// See https://core.telegram.org/bots/api#editmessagecaption
+type EditMessageCaptionRequest struct {
+	ChatID          int64                 `json:"chat_id,omitempty"`
+	MessageID       int64                 `json:"message_id,omitempty"`
+	InlineMessageID string                `json:"inline_message_id,omitempty"`
+	Caption         string                `json:"caption"`
+	ParseMode       string                `json:"parse_mode,omitempty"`
+	CaptionEntities []MessageEntity       `json:"caption_entities,omitempty"`
+	ReplyMarkup     *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
+}
+
+// EditMessageMediaRequest defines new media for a message, replacing its animation, audio, document, photo or video.
+// The message is identified either by ChatID and MessageID, or by InlineMessageID for messages sent through inline
+// queries, in which case new files can't be uploaded.
+// See https://core.telegram.org/bots/api#editmessagemedia
+type EditMessageMediaRequest struct {
+	ChatID          int64                 `json:"chat_id,omitempty"`
+	MessageID       int64                 `json:"message_id,omitempty"`
+	InlineMessageID string                `json:"inline_message_id,omitempty"`
+	Media           InputMedia            `json:"media"`
+	ReplyMarkup     *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
+}
+
+// EditMessageReplyMarkupRequest defines a new inline keyboard for a message, a nil ReplyMarkup removes the keyboard.
+// The message is identified either by ChatID and MessageID, or by InlineMessageID for messages sent through inline
+// queries.
+// See https://core.telegram.org/bots/api#editmessagereplymarkup
+type EditMessageReplyMarkupRequest struct {
+	ChatID          int64                 `json:"chat_id,omitempty"`
+	MessageID       int64                 `json:"message_id,omitempty"`
+	InlineMessageID string                `json:"inline_message_id,omitempty"`
+	ReplyMarkup     *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
+}
+
+// DeleteMessageRequest identifies a message to delete.
+// See https://core.telegram.org/bots/api#deletemessage
+type DeleteMessageRequest struct {
+	ChatID    int64 `json:"chat_id"`
+	MessageID int64 `json:"message_id"`
+}
+
+const maxDeletedMessages = 100
+
+// DeleteMessagesRequest identifies up to 100 messages of the same chat to delete. Messages that can't be found are
+// skipped.
+// See https://core.telegram.org/bots/api#deletemessages
+type DeleteMessagesRequest struct {
+	ChatID     int64   `json:"chat_id"`
+	MessageIDs []int64 `json:"message_ids"`
+}
+
+// messageAddress identifies the message targeted by an edit request.
+type messageAddress struct {
+	chatID          int64
+	messageID       int64
+	inlineMessageID string
+}
+
+// isValid returns whether the address identifies a message, either by chat and message IDs, or by inline message ID.
+func (a messageAddress) isValid() bool {
+	if a.inlineMessageID != "" {
+		return a.chatID == 0 && a.messageID == 0
+	}
+
+	return a.chatID != 0 && a.messageID != 0
+}
+
+func (r *EditMessageTextRequest) address() messageAddress {
+	return messageAddress{r.ChatID, r.MessageID, r.InlineMessageID}
+}
+
+func (r *EditMessageCaptionRequest) address() messageAddress {
+	return messageAddress{r.ChatID, r.MessageID, r.InlineMessageID}
+}
+
+func (r *EditMessageMediaRequest) address() messageAddress {
+	return messageAddress{r.ChatID, r.MessageID, r.InlineMessageID}
+}
+
+func (r *EditMessageReplyMarkupRequest) address() messageAddress {
+	return messageAddress{r.ChatID, r.MessageID, r.InlineMessageID}
+}
+
+// MarshalJSON references the files to upload by the name of the multipart/form-data part they are uploaded as.
+func (r *EditMessageMediaRequest) MarshalJSON() ([]byte, error) {
+	var media json.RawMessage
+	if r.Media != nil {
+		var err error
+		media, err = marshalMediaItem(r.Media, 0)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	type editMessageMediaRequest EditMessageMediaRequest
+	return json.Marshal(&struct {
+		*editMessageMediaRequest
+		Media json.RawMessage `json:"media"`
+	}{
+		editMessageMediaRequest: (*editMessageMediaRequest)(r),
+		Media:                   media,
+	})
+}
+
+func (r *EditMessageMediaRequest) files() map[string]*InputFile {
+	if r.Media == nil {
+		return nil
+	}
+
+	return map[string]*InputFile{
+		mediaAttachName(0): r.Media.mediaFile(),
+		thumbAttachName(0): r.Media.thumbFile(),
+	}
+}
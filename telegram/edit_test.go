@@ -0,0 +1,185 @@
+package telegram
+
+import (
+	"context"
+	"errors"
+	"mime/multipart"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestEditMessageText_ReturnEditedMessage(t *testing.T) {
+	httpClient, requests := newRecordingHttpClient(`{"ok": true, "result": {"message_id": 42, "text": "edited"}}`)
+	service, _ := newMessagingService(newExecutor(httpClient, testApiUrlFmt, testToken, nil))
+
+	message, err := service.editMessageText(context.Background(), &EditMessageTextRequest{
+		ChatID:    7,
+		MessageID: 42,
+		Text:      "edited",
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(42), message.ID)
+	assert.Equal(t, "edited", message.Text)
+	assert.JSONEq(t, `{"chat_id": 7, "message_id": 42, "text": "edited"}`, requests[endpointEditMessageText][0])
+}
+
+func TestEditMessageCaption_ReturnNilMessageForInlineMessages(t *testing.T) {
+	httpClient, requests := newRecordingHttpClient(`{"ok": true, "result": true}`)
+	service, _ := newMessagingService(newExecutor(httpClient, testApiUrlFmt, testToken, nil))
+
+	message, err := service.editMessageCaption(context.Background(), &EditMessageCaptionRequest{
+		InlineMessageID: "inline",
+		Caption:         "edited",
+	})
+
+	assert.NoError(t, err)
+	assert.Nil(t, message)
+	assert.JSONEq(t, `{"inline_message_id": "inline", "caption": "edited"}`, requests[endpointEditMessageCaption][0])
+}
+
+func TestEditMessageReplyMarkup_ReturnErrorIfAddressIsInvalid(t *testing.T) {
+	service, _ := newMessagingService(newExecutor(&mockHttpClient{}, testApiUrlFmt, testToken, nil))
+
+	requests := []*EditMessageReplyMarkupRequest{
+		{},
+		{ChatID: 7},
+		{MessageID: 42},
+		{ChatID: 7, MessageID: 42, InlineMessageID: "inline"},
+	}
+	for _, request := range requests {
+		_, err := service.editMessageReplyMarkup(context.Background(), request)
+
+		assert.Equal(t, errInvalidMessageAddress, err)
+	}
+}
+
+func TestEditMessageText_ReturnErrorMatchingErrMessageNotModified(t *testing.T) {
+	httpClient := &mockHttpClient{}
+	httpClient.On("Do", mock.Anything).Return(newResponse(http.StatusBadRequest, `{"ok": false, "error_code": 400, `+
+		`"description": "Bad Request: message is not modified: specified new message content and reply markup are `+
+		`exactly the same as a current content and reply markup of the message"}`), nil)
+	service, _ := newMessagingService(newExecutor(httpClient, testApiUrlFmt, testToken, nil))
+
+	_, err := service.editMessageText(context.Background(), &EditMessageTextRequest{
+		ChatID:    7,
+		MessageID: 42,
+		Text:      "same",
+	})
+
+	assert.True(t, errors.Is(err, ErrMessageNotModified))
+	var apiErr *APIError
+	assert.True(t, errors.As(err, &apiErr))
+}
+
+func TestEditMessageText_ReturnErrorNotMatchingErrMessageNotModified(t *testing.T) {
+	httpClient := &mockHttpClient{}
+	httpClient.On("Do", mock.Anything).Return(newResponse(http.StatusBadRequest,
+		`{"ok": false, "error_code": 400, "description": "Bad Request: message to edit not found"}`), nil)
+	service, _ := newMessagingService(newExecutor(httpClient, testApiUrlFmt, testToken, nil))
+
+	_, err := service.editMessageText(context.Background(), &EditMessageTextRequest{
+		ChatID:    7,
+		MessageID: 42,
+		Text:      "edited",
+	})
+
+	assert.Error(t, err)
+	assert.False(t, errors.Is(err, ErrMessageNotModified))
+}
+
+func TestEditMessageMedia_AttachUploadedFile(t *testing.T) {
+	var form *multipart.Form
+	httpClient := httpClientFunc(func(request *http.Request) (*http.Response, error) {
+		form = readMultipartForm(t, request)
+		return newResponse(http.StatusOK, `{"ok": true, "result": {"message_id": 1}}`), nil
+	})
+	service, _ := newMessagingService(newExecutor(httpClient, testApiUrlFmt, testToken, nil))
+
+	_, err := service.editMessageMedia(context.Background(), &EditMessageMediaRequest{
+		ChatID:    7,
+		MessageID: 42,
+		Media:     &InputMediaPhoto{Media: FileFromReader("photo.jpg", strings.NewReader("photo"))},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "7", form.Value["chat_id"][0])
+	assert.JSONEq(t, `{"type": "photo", "media": "attach://media0"}`, form.Value["media"][0])
+	assert.Equal(t, "photo", readFormFile(t, form.File["media0"][0]))
+}
+
+func TestEditMessageMedia_ReplaceMediaWithAnimation(t *testing.T) {
+	var form *multipart.Form
+	httpClient := httpClientFunc(func(request *http.Request) (*http.Response, error) {
+		form = readMultipartForm(t, request)
+		return newResponse(http.StatusOK, `{"ok": true, "result": {"message_id": 1}}`), nil
+	})
+	service, _ := newMessagingService(newExecutor(httpClient, testApiUrlFmt, testToken, nil))
+
+	_, err := service.editMessageMedia(context.Background(), &EditMessageMediaRequest{
+		InlineMessageID: "inline",
+		Media: &InputMediaAnimation{
+			Media:    FileFromReader("animation.mp4", strings.NewReader("animation")),
+			Thumb:    FileFromReader("thumb.jpg", strings.NewReader("thumb")),
+			Caption:  "caption",
+			Duration: 3,
+		},
+	})
+
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{
+		"type": "animation",
+		"media": "attach://media0",
+		"thumb": "attach://thumb0",
+		"caption": "caption",
+		"duration": 3
+	}`, form.Value["media"][0])
+	assert.Equal(t, "animation", readFormFile(t, form.File["media0"][0]))
+	assert.Equal(t, "thumb", readFormFile(t, form.File["thumb0"][0]))
+}
+
+func TestEditMessageMedia_ReturnErrorIfMediaIsMissing(t *testing.T) {
+	service, _ := newMessagingService(newExecutor(&mockHttpClient{}, testApiUrlFmt, testToken, nil))
+
+	_, err := service.editMessageMedia(context.Background(), &EditMessageMediaRequest{ChatID: 7, MessageID: 42})
+
+	assert.Equal(t, errMissingFile, err)
+
+	_, err = service.editMessageMedia(context.Background(), &EditMessageMediaRequest{
+		ChatID:    7,
+		MessageID: 42,
+		Media:     (*InputMediaPhoto)(nil),
+	})
+
+	assert.Equal(t, errMissingFile, err)
+}
+
+func TestDeleteMessages_SendMessageIDs(t *testing.T) {
+	httpClient, requests := newRecordingHttpClient(`{"ok": true, "result": true}`)
+	service, _ := newMessagingService(newExecutor(httpClient, testApiUrlFmt, testToken, nil))
+
+	deleted, err := service.deleteMessages(context.Background(), &DeleteMessagesRequest{
+		ChatID:     7,
+		MessageIDs: []int64{1, 2},
+	})
+
+	assert.NoError(t, err)
+	assert.True(t, deleted)
+	assert.JSONEq(t, `{"chat_id": 7, "message_ids": [1, 2]}`, requests[endpointDeleteMessages][0])
+}
+
+func TestDeleteMessages_ReturnErrorIfCountIsInvalid(t *testing.T) {
+	service, _ := newMessagingService(newExecutor(&mockHttpClient{}, testApiUrlFmt, testToken, nil))
+
+	for _, count := range []int{0, 101} {
+		request := &DeleteMessagesRequest{ChatID: 7, MessageIDs: make([]int64, count)}
+
+		_, err := service.deleteMessages(context.Background(), request)
+
+		assert.Equal(t, errInvalidDeleteCount, err)
+	}
+}
@@ -1,8 +1,51 @@
 package telegram // import "heytobi.dev/fuse/telegram"
 
 const (
-	endpointGetUpdates    = "getUpdates"    // https://core.telegram.org/bots/api#getupdates
-	endpointSetWebhook    = "setWebhook"    // https://core.telegram.org/bots/api#setwebhook
-	endpointDeleteWebhook = "deleteWebhook" // https://core.telegram.org/bots/api#deletewebhook
-	endpointSendMessage   = "sendMessage"   // https://core.telegram.org/bots/api#sendmessage
+	endpointGetUpdates             = "getUpdates"             // https://core.telegram.org/bots/api#getupdates
+	endpointSetWebhook             = "setWebhook"             // https://core.telegram.org/bots/api#setwebhook
+	endpointDeleteWebhook          = "deleteWebhook"          // https://core.telegram.org/bots/api#deletewebhook
+	endpointSendMessage            = "sendMessage"            // https://core.telegram.org/bots/api#sendmessage
+	endpointAnswerCallbackQuery    = "answerCallbackQuery"    // https://core.telegram.org/bots/api#answercallbackquery
+	endpointSendPhoto              = "sendPhoto"              // https://core.telegram.org/bots/api#sendphoto
+	endpointSendDocument           = "sendDocument"           // https://core.telegram.org/bots/api#senddocument
+	endpointSendAudio              = "sendAudio"              // https://core.telegram.org/bots/api#sendaudio
+	endpointSendVideo              = "sendVideo"              // https://core.telegram.org/bots/api#sendvideo
+	endpointSendVoice              = "sendVoice"              // https://core.telegram.org/bots/api#sendvoice
+	endpointSendAnimation          = "sendAnimation"          // https://core.telegram.org/bots/api#sendanimation
+	endpointSendMediaGroup         = "sendMediaGroup"         // https://core.telegram.org/bots/api#sendmediagroup
+	endpointGetFile                = "getFile"                // https://core.telegram.org/bots/api#getfile
+	endpointEditMessageText        = "editMessageText"        // https://core.telegram.org/bots/api#editmessagetext
+	endpointEditMessageCaption     = "editMessageCaption"     // https://core.telegram.org/bots/api#editmessagecaption
+	endpointEditMessageMedia       = "editMessageMedia"       // https://core.telegram.org/bots/api#editmessagemedia
+	endpointEditMessageReplyMarkup = "editMessageReplyMarkup" // https://core.telegram.org/bots/api#editmessagereplymarkup
+	endpointDeleteMessage          = "deleteMessage"          // https://core.telegram.org/bots/api#deletemessage
+	endpointDeleteMessages         = "deleteMessages"         // https://core.telegram.org/bots/api#deletemessages
+	endpointAnswerInlineQuery      = "answerInlineQuery"      // https://core.telegram.org/bots/api#answerinlinequery
+	endpointSetMyCommands          = "setMyCommands"          // https://core.telegram.org/bots/api#setmycommands
+	endpointGetMyCommands          = "getMyCommands"          // https://core.telegram.org/bots/api#getmycommands
+	endpointDeleteMyCommands       = "deleteMyCommands"       // https://core.telegram.org/bots/api#deletemycommands
+	endpointGetMe                  = "getMe"                  // https://core.telegram.org/bots/api#getme
+	endpointGetWebhookInfo         = "getWebhookInfo"         // https://core.telegram.org/bots/api#getwebhookinfo
+	endpointLogOut                 = "logOut"                 // https://core.telegram.org/bots/api#logout
+	endpointClose                  = "close"                  // https://core.telegram.org/bots/api#close
 )
+
+// idempotentEndpoints are the endpoints that can safely be called again when it's unknown whether a call succeeded.
+var idempotentEndpoints = map[string]bool{
+	endpointGetUpdates:             true,
+	endpointSetWebhook:             true,
+	endpointDeleteWebhook:          true,
+	endpointAnswerCallbackQuery:    true,
+	endpointGetFile:                true,
+	endpointEditMessageText:        true,
+	endpointEditMessageCaption:     true,
+	endpointEditMessageReplyMarkup: true,
+	endpointDeleteMessage:          true,
+	endpointDeleteMessages:         true,
+	endpointAnswerInlineQuery:      true,
+	endpointSetMyCommands:          true,
+	endpointGetMyCommands:          true,
+	endpointDeleteMyCommands:       true,
+	endpointGetMe:                  true,
+	endpointGetWebhookInfo:         true,
+}
@@ -0,0 +1,185 @@
+package telegram // import "heytobi.dev/fuse/telegram"
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// ErrMessageNotModified matches the error returned when editing a message without changing its content or its reply
+// markup, e.g. errors.Is(err, ErrMessageNotModified). The message is left as is, so it can usually be ignored.
+var ErrMessageNotModified = errors.New("message is not modified")
+
+// APIError is returned when the Bot API responds to a request with an error, it can be inspected with errors.As.
+// See https://core.telegram.org/bots/api#making-requests
+type APIError struct {
+	ErrorCode          int
+	Description        string
+	ResponseParameters *ResponseParameters
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("telegram api error %d: %s", e.ErrorCode, e.Description)
+}
+
+// Is makes errors returned by the API for well known reasons match the corresponding sentinel errors, such as
+// ErrMessageNotModified.
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrMessageNotModified:
+		return e.ErrorCode == http.StatusBadRequest && strings.Contains(e.Description, "message is not modified")
+	}
+
+	return false
+}
+
+// ResponseParameters describes why a request was unsuccessful.
+// See https://core.telegram.org/bots/api#responseparameters
+type ResponseParameters struct {
+	MigrateToChatID int64 `json:"migrate_to_chat_id"`
+	RetryAfter      int   `json:"retry_after"`
+}
+
+// apiResponse is the envelope of every Bot API response.
+type apiResponse struct {
+	Ok          bool                `json:"ok"`
+	Result      json.RawMessage     `json:"result,omitempty"`
+	ErrorCode   int                 `json:"error_code,omitempty"`
+	Description string              `json:"description,omitempty"`
+	Parameters  *ResponseParameters `json:"parameters,omitempty"`
+}
+
+// executor makes requests to the Bot API. Every API method goes through it, so that requests are built and responses
+// are handled consistently.
+type executor struct {
+	httpClient  httpClient
+	apiUrlFmt   string
+	token       string
+	retryPolicy *RetryPolicy
+	rateLimiter RateLimiter
+}
+
+// chatRequest is implemented by requests sending messages to a chat, which are paced by the executor's rate limiter.
+type chatRequest interface {
+	targetChatID() int64
+}
+
+func newExecutor(httpClient httpClient, apiUrlFmt, token string, retryPolicy *RetryPolicy) *executor {
+	return &executor{
+		httpClient:  httpClient,
+		apiUrlFmt:   apiUrlFmt,
+		token:       token,
+		retryPolicy: retryPolicy,
+	}
+}
+
+// execute calls the given endpoint with the given parameters, decoding the result of the call into result, unless it
+// is nil. If the API responds with an error, an *APIError is returned.
+//
+// Failed calls are retried according to the executor's retry policy, unless they upload a file that can't be read
+// again. Requests sending messages wait for the executor's rate limiter, if any.
+func (e *executor) execute(ctx context.Context, endpoint string, params any, result any) error {
+	body, err := newRequestBody(params)
+	if err != nil {
+		return errors.Wrap(err, fmt.Sprintf("failed to marshal %s request body", endpoint))
+	}
+
+	maxAttempts := e.retryPolicy.maxAttempts()
+	for attempt := 1; ; attempt++ {
+		if request, ok := params.(chatRequest); ok && e.rateLimiter != nil {
+			err = e.rateLimiter.Wait(ctx, request.targetChatID())
+			if err != nil {
+				return errors.Wrap(err, fmt.Sprintf("failed to wait for rate limiter before %s request", endpoint))
+			}
+		}
+
+		err = e.do(ctx, endpoint, body, result)
+		if err == nil || attempt >= maxAttempts || !body.isReplayable() || !e.retryPolicy.shouldRetry(endpoint, err) {
+			return err
+		}
+
+		delay := e.retryPolicy.backoff(attempt, err)
+		logrus.WithError(err).WithField("endpoint", endpoint).Warnf("request failed, retrying in %s", delay)
+
+		if waitErr := wait(ctx, delay); waitErr != nil {
+			return err
+		}
+	}
+}
+
+// do makes a single request to the given endpoint.
+func (e *executor) do(ctx context.Context, endpoint string, body *requestBody, result any) error {
+	url := fmt.Sprintf(e.apiUrlFmt, e.token, endpoint)
+
+	bodyReader, contentType, err := body.reader()
+	if err != nil {
+		return errors.Wrap(err, fmt.Sprintf("failed to create %s request body", endpoint))
+	}
+	// closing the body stops the upload of any file that wasn't fully sent, e.g. if the request failed.
+	defer bodyReader.Close()
+
+	request, err := http.NewRequestWithContext(ctx, httpPost, url, bodyReader)
+	if err != nil {
+		return errors.Wrap(err, fmt.Sprintf("failed to create %s request", endpoint))
+	}
+	request.Header.Set("Content-Type", contentType)
+
+	response, err := e.httpClient.Do(request)
+	if err != nil {
+		return errors.Wrap(err, fmt.Sprintf("%s request failed", endpoint))
+	}
+	defer func(body io.ReadCloser) {
+		err := body.Close()
+		if err != nil {
+			logrus.WithError(err).Error("failed to close response body")
+		}
+	}(response.Body)
+
+	responseBody, err := io.ReadAll(response.Body)
+	if err != nil {
+		return errors.Wrap(err, fmt.Sprintf("failed to read %s response body", endpoint))
+	}
+
+	var resp apiResponse
+	err = json.Unmarshal(responseBody, &resp)
+	if err != nil {
+		if response.StatusCode != http.StatusOK {
+			// responses that aren't from the Bot API itself, e.g. from a proxy, might not be JSON.
+			return &APIError{
+				ErrorCode:   response.StatusCode,
+				Description: strings.TrimSpace(string(responseBody)),
+			}
+		}
+		return errors.Wrap(err, fmt.Sprintf("failed to unmarshal %s response", endpoint))
+	}
+
+	if !resp.Ok {
+		errorCode := resp.ErrorCode
+		if errorCode == 0 {
+			errorCode = response.StatusCode
+		}
+
+		return &APIError{
+			ErrorCode:          errorCode,
+			Description:        resp.Description,
+			ResponseParameters: resp.Parameters,
+		}
+	}
+
+	if result == nil || len(resp.Result) == 0 {
+		return nil
+	}
+
+	err = json.Unmarshal(resp.Result, result)
+	if err != nil {
+		return errors.Wrap(err, fmt.Sprintf("failed to unmarshal %s result", endpoint))
+	}
+
+	return nil
+}
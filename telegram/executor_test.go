@@ -0,0 +1,84 @@
+package telegram
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestExecute_DecodeResult(t *testing.T) {
+	httpClient, requests := newRecordingHttpClient(`{"ok": true, "result": {"message_id": 7}}`)
+	executor := newExecutor(httpClient, testApiUrlFmt, testToken, nil)
+
+	var message Message
+	err := executor.execute(context.Background(), endpointSendMessage, &SendMessageRequest{ChatID: 1}, &message)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(7), message.ID)
+	assert.Len(t, requests[endpointSendMessage], 1)
+}
+
+func TestExecute_ReturnApiErrorWithResponseParameters(t *testing.T) {
+	httpClient := &mockHttpClient{}
+	httpClient.On("Do", mock.Anything).Return(newResponse(
+		http.StatusTooManyRequests,
+		`{"ok": false, "error_code": 429, "description": "Too Many Requests: retry after 5", `+
+			`"parameters": {"retry_after": 5}}`,
+	), nil)
+	executor := newExecutor(httpClient, testApiUrlFmt, testToken, nil)
+
+	err := executor.execute(context.Background(), endpointSendMessage, &SendMessageRequest{}, nil)
+
+	var apiErr *APIError
+	assert.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, http.StatusTooManyRequests, apiErr.ErrorCode)
+	assert.Equal(t, "Too Many Requests: retry after 5", apiErr.Description)
+	assert.Equal(t, 5, apiErr.ResponseParameters.RetryAfter)
+}
+
+func TestExecute_ReturnApiErrorWithMigrateToChatID(t *testing.T) {
+	httpClient := &mockHttpClient{}
+	httpClient.On("Do", mock.Anything).Return(newResponse(
+		http.StatusBadRequest,
+		`{"ok": false, "error_code": 400, "description": "Bad Request: group chat was upgraded to a supergroup chat", `+
+			`"parameters": {"migrate_to_chat_id": -1001234}}`,
+	), nil)
+	executor := newExecutor(httpClient, testApiUrlFmt, testToken, nil)
+
+	err := executor.execute(context.Background(), endpointSendMessage, &SendMessageRequest{}, nil)
+
+	var apiErr *APIError
+	assert.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, int64(-1001234), apiErr.ResponseParameters.MigrateToChatID)
+}
+
+func TestExecute_ReturnApiErrorIfResponseIsNotJson(t *testing.T) {
+	httpClient := &mockHttpClient{}
+	httpClient.On("Do", mock.Anything).Return(newResponse(http.StatusBadGateway, "Bad Gateway\n"), nil)
+	executor := newExecutor(httpClient, testApiUrlFmt, testToken, nil)
+
+	err := executor.execute(context.Background(), endpointSendMessage, &SendMessageRequest{}, nil)
+
+	var apiErr *APIError
+	assert.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, http.StatusBadGateway, apiErr.ErrorCode)
+	assert.Equal(t, "Bad Gateway", apiErr.Description)
+	assert.Nil(t, apiErr.ResponseParameters)
+}
+
+func TestExecute_ReturnErrorIfRequestFails(t *testing.T) {
+	httpClient := httpClientFunc(func(request *http.Request) (*http.Response, error) {
+		return nil, errors.New("error")
+	})
+	executor := newExecutor(httpClient, testApiUrlFmt, testToken, nil)
+
+	err := executor.execute(context.Background(), endpointSendMessage, &SendMessageRequest{}, nil)
+
+	var apiErr *APIError
+	assert.False(t, errors.As(err, &apiErr))
+	assert.EqualError(t, err, "sendMessage request failed: error")
+}
@@ -0,0 +1,19 @@
+package telegram // import "heytobi.dev/fuse/telegram"
+
+// maxCloudDownloadSize is the size of the largest file that can be downloaded through the cloud Bot API.
+// See https://core.telegram.org/bots/api#getfile
+const maxCloudDownloadSize = 20 * 1024 * 1024
+
+// File represents a file ready to be downloaded. When using a local Bot API server, FilePath is the absolute path of
+// the file on the server's filesystem.
+// See https://core.telegram.org/bots/api#file
+type File struct {
+	FileID       string `json:"file_id"`
+	FileUniqueID string `json:"file_unique_id"`
+	FileSize     int64  `json:"file_size"`
+	FilePath     string `json:"file_path"`
+}
+
+type getFileRequest struct {
+	FileID string `json:"file_id"`
+}
@@ -0,0 +1,116 @@
+package telegram // import "heytobi.dev/fuse/telegram"
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+type fileService struct {
+	executor    *executor
+	httpClient  httpClient
+	fileUrlFmt  string
+	token       string
+	localServer bool
+}
+
+func newFileService(
+	executor *executor,
+	httpClient httpClient,
+	fileUrlFmt, token string,
+	localServer bool,
+) (*fileService, error) {
+	return &fileService{
+		executor:    executor,
+		httpClient:  httpClient,
+		fileUrlFmt:  fileUrlFmt,
+		token:       token,
+		localServer: localServer,
+	}, nil
+}
+
+// getFile gets the information needed to download a file.
+// See https://core.telegram.org/bots/api#getfile
+func (s *fileService) getFile(ctx context.Context, fileID string) (*File, error) {
+	if fileID == "" {
+		return nil, errMissingFileID
+	}
+
+	var file File
+	err := s.executor.execute(ctx, endpointGetFile, getFileRequest{FileID: fileID}, &file)
+	if err != nil {
+		return nil, err
+	}
+
+	return &file, nil
+}
+
+// downloadURL returns the URL the given file can be downloaded from.
+func (s *fileService) downloadURL(file *File) string {
+	return fmt.Sprintf(s.fileUrlFmt, s.token, file.FilePath)
+}
+
+// downloadFile writes the content of the file with the given ID to the given writer, as it's downloaded.
+func (s *fileService) downloadFile(ctx context.Context, fileID string, writer io.Writer) error {
+	file, err := s.getFile(ctx, fileID)
+	if err != nil {
+		return errors.Wrap(err, "failed to get file")
+	}
+
+	// local servers give the path of the file on their filesystem, rather than a path to download it from.
+	if s.localServer && filepath.IsAbs(file.FilePath) {
+		return copyLocalFile(file.FilePath, writer)
+	}
+
+	if !s.localServer && file.FileSize > maxCloudDownloadSize {
+		return errFileTooLarge
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, s.downloadURL(file), nil)
+	if err != nil {
+		return errors.Wrap(err, "failed to create file download request")
+	}
+
+	response, err := s.httpClient.Do(request)
+	if err != nil {
+		return errors.Wrap(err, "file download request failed")
+	}
+	defer func(body io.ReadCloser) {
+		err := body.Close()
+		if err != nil {
+			logrus.WithError(err).Error("failed to close response body")
+		}
+	}(response.Body)
+
+	if response.StatusCode != http.StatusOK {
+		return &APIError{ErrorCode: response.StatusCode, Description: "failed to download file"}
+	}
+
+	_, err = io.Copy(writer, response.Body)
+	if err != nil {
+		return errors.Wrap(err, "failed to download file")
+	}
+
+	return nil
+}
+
+func copyLocalFile(path string, writer io.Writer) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return errors.Wrap(err, "failed to open file served by local bot api server")
+	}
+	defer file.Close()
+
+	_, err = io.Copy(writer, file)
+	if err != nil {
+		return errors.Wrap(err, "failed to read file served by local bot api server")
+	}
+
+	return nil
+}
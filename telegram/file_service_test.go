@@ -0,0 +1,113 @@
+package telegram
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testFileUrlFmt = "https://api.telegram.local/file/bot%s/%s"
+
+func TestGetFile_GetFileSuccessfully(t *testing.T) {
+	httpClient, requests := newRecordingHttpClient(
+		`{"ok": true, "result": {"file_id": "id", "file_size": 42, "file_path": "documents/file_1.pdf"}}`,
+	)
+	service := newTestFileService(httpClient, false)
+
+	file, err := service.getFile(context.Background(), "id")
+
+	assert.NoError(t, err)
+	assert.Equal(t, &File{FileID: "id", FileSize: 42, FilePath: "documents/file_1.pdf"}, file)
+	assert.Equal(t, `{"file_id":"id"}`, requests[endpointGetFile][0])
+}
+
+func TestGetFile_ReturnErrorIfFileIDIsMissing(t *testing.T) {
+	service := newTestFileService(&mockHttpClient{}, false)
+
+	file, err := service.getFile(context.Background(), "")
+
+	assert.Nil(t, file)
+	assert.Equal(t, errMissingFileID, err)
+}
+
+func TestDownloadFile_StreamFileContent(t *testing.T) {
+	var downloadURL string
+	httpClient := httpClientFunc(func(request *http.Request) (*http.Response, error) {
+		if request.Method == http.MethodGet {
+			downloadURL = request.URL.String()
+			return newResponse(http.StatusOK, "content"), nil
+		}
+		return newResponse(http.StatusOK, `{"ok": true, "result": {"file_path": "voice/file_2.oga"}}`), nil
+	})
+	service := newTestFileService(httpClient, false)
+
+	var content bytes.Buffer
+	err := service.downloadFile(context.Background(), "id", &content)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "content", content.String())
+	assert.Equal(t, "https://api.telegram.local/file/bottoken/voice/file_2.oga", downloadURL)
+}
+
+func TestDownloadFile_ReturnErrorIfFileExceedsCloudLimit(t *testing.T) {
+	httpClient, _ := newRecordingHttpClient(`{"ok": true, "result": {"file_size": 20971521, "file_path": "video.mp4"}}`)
+	service := newTestFileService(httpClient, false)
+
+	err := service.downloadFile(context.Background(), "id", io.Discard)
+
+	assert.Equal(t, errFileTooLarge, err)
+}
+
+func TestDownloadFile_ReturnApiErrorIfDownloadFails(t *testing.T) {
+	httpClient := httpClientFunc(func(request *http.Request) (*http.Response, error) {
+		if request.Method == http.MethodGet {
+			return newResponse(http.StatusNotFound, "Not Found"), nil
+		}
+		return newResponse(http.StatusOK, `{"ok": true, "result": {"file_path": "voice/file_2.oga"}}`), nil
+	})
+	service := newTestFileService(httpClient, false)
+
+	err := service.downloadFile(context.Background(), "id", io.Discard)
+
+	var apiErr *APIError
+	assert.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusNotFound, apiErr.ErrorCode)
+}
+
+func TestDownloadFile_ReadFileFromLocalServerFilesystem(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "file_3.pdf")
+	assert.NoError(t, os.WriteFile(path, []byte("local content"), 0o600))
+
+	response := `{"ok": true, "result": {"file_size": 104857600, "file_path": ` + quote(path) + `}}`
+	httpClient, _ := newRecordingHttpClient(response)
+	service := newTestFileService(httpClient, true)
+
+	var content bytes.Buffer
+	err := service.downloadFile(context.Background(), "id", &content)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "local content", content.String())
+}
+
+func newTestFileService(httpClient httpClient, localServer bool) *fileService {
+	service, _ := newFileService(
+		newExecutor(httpClient, testApiUrlFmt, "token", nil),
+		httpClient,
+		testFileUrlFmt,
+		"token",
+		localServer,
+	)
+
+	return service
+}
+
+func quote(s string) string {
+	return `"` + strings.ReplaceAll(s, `\`, `\\`) + `"`
+}
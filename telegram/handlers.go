@@ -0,0 +1,94 @@
+package telegram // import "heytobi.dev/fuse/telegram"
+
+// RegisterUpdateHandler registers the given handler function to handle every update of the given type, e.g.
+// UpdateTypeCallbackQuery. Messages are handled by the handlers registered through RegisterHandler and
+// RegisterDefaultHandler instead.
+func (b *Bot) RegisterUpdateHandler(updateType string, handler HandlerFunc) error {
+	if !isSupportedUpdateType(updateType) || updateType == UpdateTypeMessage {
+		return errInvalidUpdateType
+	}
+
+	if _, handlerExists := b.updateHandlers[updateType]; handlerExists {
+		return errUpdateHandlerExists
+	}
+
+	b.updateHandlers[updateType] = handler
+
+	return nil
+}
+
+// OnEditedMessage registers the given handler function to handle edited messages.
+func (b *Bot) OnEditedMessage(handler HandlerFunc) error {
+	return b.RegisterUpdateHandler(UpdateTypeEditedMessage, handler)
+}
+
+// OnChannelPost registers the given handler function to handle channel posts.
+func (b *Bot) OnChannelPost(handler HandlerFunc) error {
+	return b.RegisterUpdateHandler(UpdateTypeChannelPost, handler)
+}
+
+// OnEditedChannelPost registers the given handler function to handle edited channel posts.
+func (b *Bot) OnEditedChannelPost(handler HandlerFunc) error {
+	return b.RegisterUpdateHandler(UpdateTypeEditedChannelPost, handler)
+}
+
+// OnInlineQuery registers the given handler function to handle inline queries.
+func (b *Bot) OnInlineQuery(handler HandlerFunc) error {
+	return b.RegisterUpdateHandler(UpdateTypeInlineQuery, handler)
+}
+
+// OnChosenInlineResult registers the given handler function to handle inline query results chosen by users.
+func (b *Bot) OnChosenInlineResult(handler HandlerFunc) error {
+	return b.RegisterUpdateHandler(UpdateTypeChosenInlineResult, handler)
+}
+
+// OnCallbackQuery registers the given handler function to handle callback queries, sent when inline keyboard buttons
+// are pressed.
+func (b *Bot) OnCallbackQuery(handler HandlerFunc) error {
+	return b.RegisterUpdateHandler(UpdateTypeCallbackQuery, handler)
+}
+
+// OnShippingQuery registers the given handler function to handle shipping queries.
+func (b *Bot) OnShippingQuery(handler HandlerFunc) error {
+	return b.RegisterUpdateHandler(UpdateTypeShippingQuery, handler)
+}
+
+// OnPreCheckoutQuery registers the given handler function to handle pre-checkout queries.
+func (b *Bot) OnPreCheckoutQuery(handler HandlerFunc) error {
+	return b.RegisterUpdateHandler(UpdateTypePreCheckoutQuery, handler)
+}
+
+// OnPoll registers the given handler function to handle poll state updates.
+func (b *Bot) OnPoll(handler HandlerFunc) error {
+	return b.RegisterUpdateHandler(UpdateTypePoll, handler)
+}
+
+// OnPollAnswer registers the given handler function to handle answers to non-anonymous polls.
+func (b *Bot) OnPollAnswer(handler HandlerFunc) error {
+	return b.RegisterUpdateHandler(UpdateTypePollAnswer, handler)
+}
+
+// RegisterUpdateHandler registers the given handler function to handle every update of the given type.
+// See Bot.RegisterUpdateHandler.
+func (g *Group) RegisterUpdateHandler(updateType string, handler HandlerFunc) error {
+	return g.bot.RegisterUpdateHandler(updateType, g.wrap(handler))
+}
+
+func isSupportedUpdateType(updateType string) bool {
+	switch updateType {
+	case UpdateTypeMessage,
+		UpdateTypeEditedMessage,
+		UpdateTypeChannelPost,
+		UpdateTypeEditedChannelPost,
+		UpdateTypeInlineQuery,
+		UpdateTypeChosenInlineResult,
+		UpdateTypeCallbackQuery,
+		UpdateTypeShippingQuery,
+		UpdateTypePreCheckoutQuery,
+		UpdateTypePoll,
+		UpdateTypePollAnswer:
+		return true
+	}
+
+	return false
+}
@@ -0,0 +1,102 @@
+package telegram
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegisterUpdateHandler_DispatchEveryUpdateType(t *testing.T) {
+	httpClient, _ := newRecordingHttpClient(`{"ok": true, "result": true}`)
+	bot, _ := NewBot(&Config{Token: "test"}, httpClient)
+
+	var handled []string
+	record := func(updateType string) HandlerFunc {
+		return func(ctx context.Context, update *Update) error {
+			handled = append(handled, updateType)
+			return nil
+		}
+	}
+
+	assert.NoError(t, bot.OnEditedMessage(record(UpdateTypeEditedMessage)))
+	assert.NoError(t, bot.OnChannelPost(record(UpdateTypeChannelPost)))
+	assert.NoError(t, bot.OnEditedChannelPost(record(UpdateTypeEditedChannelPost)))
+	assert.NoError(t, bot.OnInlineQuery(record(UpdateTypeInlineQuery)))
+	assert.NoError(t, bot.OnChosenInlineResult(record(UpdateTypeChosenInlineResult)))
+	assert.NoError(t, bot.OnCallbackQuery(record(UpdateTypeCallbackQuery)))
+	assert.NoError(t, bot.OnShippingQuery(record(UpdateTypeShippingQuery)))
+	assert.NoError(t, bot.OnPreCheckoutQuery(record(UpdateTypePreCheckoutQuery)))
+	assert.NoError(t, bot.OnPoll(record(UpdateTypePoll)))
+	assert.NoError(t, bot.OnPollAnswer(record(UpdateTypePollAnswer)))
+
+	updates := []*Update{
+		{EditedMessage: &Message{}},
+		{ChannelPost: &Message{}},
+		{EditedChannelPost: &Message{}},
+		{InlineQuery: &InlineQuery{}},
+		{ChosenInlineResult: &ChosenInlineResult{}},
+		{CallbackQuery: &CallbackQuery{}},
+		{ShippingQuery: &ShippingQuery{}},
+		{PreCheckoutQuery: &PreCheckoutQuery{}},
+		{Poll: &Poll{}},
+		{PollAnswer: &PollAnswer{}},
+	}
+
+	var expected []string
+	for _, update := range updates {
+		assert.NoError(t, bot.ProcessUpdate(context.Background(), update))
+		expected = append(expected, update.Type())
+	}
+
+	assert.Equal(t, expected, handled)
+}
+
+func TestRegisterUpdateHandler_ReturnErrorIfHandlerExists(t *testing.T) {
+	bot, _ := NewBot(&Config{Token: "test"}, &mockHttpClient{})
+	_ = bot.OnCallbackQuery(func(ctx context.Context, update *Update) error { return nil })
+
+	err := bot.OnCallbackQuery(func(ctx context.Context, update *Update) error { return nil })
+
+	assert.Equal(t, errUpdateHandlerExists, err)
+}
+
+func TestRegisterUpdateHandler_ReturnErrorIfUpdateTypeIsInvalid(t *testing.T) {
+	bot, _ := NewBot(&Config{Token: "test"}, &mockHttpClient{})
+	handler := func(ctx context.Context, update *Update) error { return nil }
+
+	assert.Equal(t, errInvalidUpdateType, bot.RegisterUpdateHandler("invalid", handler))
+	assert.Equal(t, errInvalidUpdateType, bot.RegisterUpdateHandler(UpdateTypeMessage, handler))
+}
+
+func TestRegisterUpdateHandler_ApplyMiddleware(t *testing.T) {
+	var calls []string
+
+	bot, _ := NewBot(&Config{Token: "test"}, &mockHttpClient{})
+	bot.Use(recordingMiddleware("bot", &calls))
+	group := bot.Group(recordingMiddleware("group", &calls))
+	_ = group.RegisterUpdateHandler(UpdateTypePoll, func(ctx context.Context, update *Update) error {
+		calls = append(calls, "poll")
+		return nil
+	})
+
+	_ = bot.ProcessUpdate(context.Background(), &Update{Poll: &Poll{}})
+
+	assert.Equal(t, []string{"bot", "group", "poll"}, calls)
+}
+
+func TestUpdate_DecodeQueryIDs(t *testing.T) {
+	var update Update
+	err := json.Unmarshal([]byte(`{
+		"update_id": 1,
+		"callback_query": {"id": "callback"},
+		"shipping_query": {"id": "shipping"},
+		"pre_checkout_query": {"id": "pre_checkout"}
+	}`), &update)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "callback", update.CallbackQuery.ID)
+	assert.Equal(t, "shipping", update.ShippingQuery.ID)
+	assert.Equal(t, "pre_checkout", update.PreCheckoutQuery.ID)
+}
@@ -0,0 +1,78 @@
+package telegram // import "heytobi.dev/fuse/telegram"
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// maxInlineQueryResults is the maximum number of results of an answer to an inline query.
+// See https://core.telegram.org/bots/api#answerinlinequery
+const maxInlineQueryResults = 50
+
+// InlineQueryHandlerFunc defines functions that can answer inline queries. A nil answer leaves the query unanswered.
+type InlineQueryHandlerFunc func(ctx context.Context, query *InlineQuery) (*AnswerInlineQueryRequest, error)
+
+// AnswerInlineQueryRequest defines up to 50 results answering an inline query. More results can be loaded as the user
+// scrolls, in which case NextOffset is passed back as the Offset of the next inline query.
+// See https://core.telegram.org/bots/api#answerinlinequery
+type AnswerInlineQueryRequest struct {
+	InlineQueryID     string              `json:"inline_query_id"`
+	Results           []InlineQueryResult `json:"results"`
+	CacheTime         int                 `json:"cache_time,omitempty"`
+	IsPersonal        bool                `json:"is_personal,omitempty"`
+	NextOffset        string              `json:"next_offset,omitempty"`
+	SwitchPmText      string              `json:"switch_pm_text,omitempty"`
+	SwitchPmParameter string              `json:"switch_pm_parameter,omitempty"`
+}
+
+// MarshalJSON adds the type of each result, as expected by the Bot API.
+func (r *AnswerInlineQueryRequest) MarshalJSON() ([]byte, error) {
+	results := make([]json.RawMessage, len(r.Results))
+	for i, result := range r.Results {
+		var err error
+		results[i], err = marshalWithType(result.resultType(), result)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	type answerInlineQueryRequest AnswerInlineQueryRequest
+	return json.Marshal(&struct {
+		*answerInlineQueryRequest
+		Results []json.RawMessage `json:"results"`
+	}{
+		answerInlineQueryRequest: (*answerInlineQueryRequest)(r),
+		Results:                  results,
+	})
+}
+
+// RegisterInlineQueryHandler registers the given handler function to answer inline queries, i.e. the queries sent
+// when users type @yourbot followed by a query. The answer returned by the handler is sent with the ID of the query
+// filled in. Inline mode has to be enabled through @BotFather for the bot to receive inline queries.
+//
+// The handler replaces the one registered through OnInlineQuery, only one of them can be registered.
+func (b *Bot) RegisterInlineQueryHandler(handler InlineQueryHandlerFunc) error {
+	return b.RegisterUpdateHandler(UpdateTypeInlineQuery, b.answeringInlineQuery(handler))
+}
+
+// RegisterInlineQueryHandler registers the given handler function to answer inline queries, the handler is wrapped by
+// the group's middleware.
+// See Bot.RegisterInlineQueryHandler.
+func (g *Group) RegisterInlineQueryHandler(handler InlineQueryHandlerFunc) error {
+	return g.RegisterUpdateHandler(UpdateTypeInlineQuery, g.bot.answeringInlineQuery(handler))
+}
+
+// answeringInlineQuery adapts the given inline query handler to a HandlerFunc sending the answer of the handler.
+func (b *Bot) answeringInlineQuery(handler InlineQueryHandlerFunc) HandlerFunc {
+	return func(ctx context.Context, update *Update) error {
+		answer, err := handler(ctx, update.InlineQuery)
+		if err != nil || answer == nil {
+			return err
+		}
+
+		answer.InlineQueryID = update.InlineQuery.ID
+		_, err = b.AnswerInlineQuery(ctx, answer)
+
+		return err
+	}
+}
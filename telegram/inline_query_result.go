@@ -0,0 +1,396 @@
+package telegram // import "heytobi.dev/fuse/telegram"
+
+const (
+	inlineQueryResultTypeArticle  = "article"
+	inlineQueryResultTypePhoto    = "photo"
+	inlineQueryResultTypeGif      = "gif"
+	inlineQueryResultTypeMpeg4Gif = "mpeg4_gif"
+	inlineQueryResultTypeVideo    = "video"
+	inlineQueryResultTypeAudio    = "audio"
+	inlineQueryResultTypeVoice    = "voice"
+	inlineQueryResultTypeDocument = "document"
+	inlineQueryResultTypeLocation = "location"
+	inlineQueryResultTypeVenue    = "venue"
+	inlineQueryResultTypeContact  = "contact"
+	inlineQueryResultTypeGame     = "game"
+	inlineQueryResultTypeSticker  = "sticker"
+)
+
+// InlineQueryResult is a result of an inline query, e.g. an InlineQueryResultArticle. Results referencing a file
+// already stored on Telegram's servers are the InlineQueryResultCached variants.
+// See https://core.telegram.org/bots/api#inlinequeryresult
+type InlineQueryResult interface {
+	resultType() string
+}
+
+// InputMessageContent is the content of the message sent when an inline query result is chosen, i.e. an
+// InputTextMessageContent, an InputLocationMessageContent, an InputVenueMessageContent or an
+// InputContactMessageContent.
+// See https://core.telegram.org/bots/api#inputmessagecontent
+type InputMessageContent interface {
+	inputMessageContent()
+}
+
+// InlineQueryResultArticle represents a link to an article or a web page.
+// See https://core.telegram.org/bots/api#inlinequeryresultarticle
+type InlineQueryResultArticle struct {
+	ID                  string                `json:"id"`
+	Title               string                `json:"title"`
+	InputMessageContent InputMessageContent   `json:"input_message_content"`
+	ReplyMarkup         *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
+	URL                 string                `json:"url,omitempty"`
+	HideURL             bool                  `json:"hide_url,omitempty"`
+	Description         string                `json:"description,omitempty"`
+	ThumbURL            string                `json:"thumb_url,omitempty"`
+	ThumbWidth          int                   `json:"thumb_width,omitempty"`
+	ThumbHeight         int                   `json:"thumb_height,omitempty"`
+}
+
+// InlineQueryResultPhoto represents a link to a photo.
+// See https://core.telegram.org/bots/api#inlinequeryresultphoto
+type InlineQueryResultPhoto struct {
+	ID                  string                `json:"id"`
+	PhotoURL            string                `json:"photo_url"`
+	ThumbURL            string                `json:"thumb_url"`
+	PhotoWidth          int                   `json:"photo_width,omitempty"`
+	PhotoHeight         int                   `json:"photo_height,omitempty"`
+	Title               string                `json:"title,omitempty"`
+	Description         string                `json:"description,omitempty"`
+	Caption             string                `json:"caption,omitempty"`
+	ParseMode           string                `json:"parse_mode,omitempty"`
+	CaptionEntities     []MessageEntity       `json:"caption_entities,omitempty"`
+	ReplyMarkup         *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
+	InputMessageContent InputMessageContent   `json:"input_message_content,omitempty"`
+}
+
+// InlineQueryResultGif represents a link to an animated GIF file.
+// See https://core.telegram.org/bots/api#inlinequeryresultgif
+type InlineQueryResultGif struct {
+	ID                  string                `json:"id"`
+	GifURL              string                `json:"gif_url"`
+	GifWidth            int                   `json:"gif_width,omitempty"`
+	GifHeight           int                   `json:"gif_height,omitempty"`
+	GifDuration         int                   `json:"gif_duration,omitempty"`
+	ThumbURL            string                `json:"thumb_url"`
+	ThumbMimeType       string                `json:"thumb_mime_type,omitempty"`
+	Title               string                `json:"title,omitempty"`
+	Caption             string                `json:"caption,omitempty"`
+	ParseMode           string                `json:"parse_mode,omitempty"`
+	CaptionEntities     []MessageEntity       `json:"caption_entities,omitempty"`
+	ReplyMarkup         *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
+	InputMessageContent InputMessageContent   `json:"input_message_content,omitempty"`
+}
+
+// InlineQueryResultMpeg4Gif represents a link to a video animation, i.e. an H.264/MPEG-4 AVC video without sound.
+// See https://core.telegram.org/bots/api#inlinequeryresultmpeg4gif
+type InlineQueryResultMpeg4Gif struct {
+	ID                  string                `json:"id"`
+	Mpeg4URL            string                `json:"mpeg4_url"`
+	Mpeg4Width          int                   `json:"mpeg4_width,omitempty"`
+	Mpeg4Height         int                   `json:"mpeg4_height,omitempty"`
+	Mpeg4Duration       int                   `json:"mpeg4_duration,omitempty"`
+	ThumbURL            string                `json:"thumb_url"`
+	ThumbMimeType       string                `json:"thumb_mime_type,omitempty"`
+	Title               string                `json:"title,omitempty"`
+	Caption             string                `json:"caption,omitempty"`
+	ParseMode           string                `json:"parse_mode,omitempty"`
+	CaptionEntities     []MessageEntity       `json:"caption_entities,omitempty"`
+	ReplyMarkup         *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
+	InputMessageContent InputMessageContent   `json:"input_message_content,omitempty"`
+}
+
+// InlineQueryResultVideo represents a link to a page containing an embedded video player or a video file.
+// See https://core.telegram.org/bots/api#inlinequeryresultvideo
+type InlineQueryResultVideo struct {
+	ID                  string                `json:"id"`
+	VideoURL            string                `json:"video_url"`
+	MimeType            string                `json:"mime_type"`
+	ThumbURL            string                `json:"thumb_url"`
+	Title               string                `json:"title"`
+	Caption             string                `json:"caption,omitempty"`
+	ParseMode           string                `json:"parse_mode,omitempty"`
+	CaptionEntities     []MessageEntity       `json:"caption_entities,omitempty"`
+	VideoWidth          int                   `json:"video_width,omitempty"`
+	VideoHeight         int                   `json:"video_height,omitempty"`
+	VideoDuration       int                   `json:"video_duration,omitempty"`
+	Description         string                `json:"description,omitempty"`
+	ReplyMarkup         *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
+	InputMessageContent InputMessageContent   `json:"input_message_content,omitempty"`
+}
+
+// InlineQueryResultAudio represents a link to an MP3 audio file.
+// See https://core.telegram.org/bots/api#inlinequeryresultaudio
+type InlineQueryResultAudio struct {
+	ID                  string                `json:"id"`
+	AudioURL            string                `json:"audio_url"`
+	Title               string                `json:"title"`
+	Caption             string                `json:"caption,omitempty"`
+	ParseMode           string                `json:"parse_mode,omitempty"`
+	CaptionEntities     []MessageEntity       `json:"caption_entities,omitempty"`
+	Performer           string                `json:"performer,omitempty"`
+	AudioDuration       int                   `json:"audio_duration,omitempty"`
+	ReplyMarkup         *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
+	InputMessageContent InputMessageContent   `json:"input_message_content,omitempty"`
+}
+
+// InlineQueryResultVoice represents a link to a voice recording in an .OGG container encoded with OPUS.
+// See https://core.telegram.org/bots/api#inlinequeryresultvoice
+type InlineQueryResultVoice struct {
+	ID                  string                `json:"id"`
+	VoiceURL            string                `json:"voice_url"`
+	Title               string                `json:"title"`
+	Caption             string                `json:"caption,omitempty"`
+	ParseMode           string                `json:"parse_mode,omitempty"`
+	CaptionEntities     []MessageEntity       `json:"caption_entities,omitempty"`
+	VoiceDuration       int                   `json:"voice_duration,omitempty"`
+	ReplyMarkup         *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
+	InputMessageContent InputMessageContent   `json:"input_message_content,omitempty"`
+}
+
+// InlineQueryResultDocument represents a link to a PDF or a ZIP file.
+// See https://core.telegram.org/bots/api#inlinequeryresultdocument
+type InlineQueryResultDocument struct {
+	ID                  string                `json:"id"`
+	Title               string                `json:"title"`
+	Caption             string                `json:"caption,omitempty"`
+	ParseMode           string                `json:"parse_mode,omitempty"`
+	CaptionEntities     []MessageEntity       `json:"caption_entities,omitempty"`
+	DocumentURL         string                `json:"document_url"`
+	MimeType            string                `json:"mime_type"`
+	Description         string                `json:"description,omitempty"`
+	ReplyMarkup         *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
+	InputMessageContent InputMessageContent   `json:"input_message_content,omitempty"`
+	ThumbURL            string                `json:"thumb_url,omitempty"`
+	ThumbWidth          int                   `json:"thumb_width,omitempty"`
+	ThumbHeight         int                   `json:"thumb_height,omitempty"`
+}
+
+// InlineQueryResultLocation represents a location on a map.
+// See https://core.telegram.org/bots/api#inlinequeryresultlocation
+type InlineQueryResultLocation struct {
+	ID                   string                `json:"id"`
+	Latitude             float32               `json:"latitude"`
+	Longitude            float32               `json:"longitude"`
+	Title                string                `json:"title"`
+	HorizontalAccuracy   float32               `json:"horizontal_accuracy,omitempty"`
+	LivePeriod           int                   `json:"live_period,omitempty"`
+	Heading              int                   `json:"heading,omitempty"`
+	ProximityAlertRadius int                   `json:"proximity_alert_radius,omitempty"`
+	ReplyMarkup          *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
+	InputMessageContent  InputMessageContent   `json:"input_message_content,omitempty"`
+	ThumbURL             string                `json:"thumb_url,omitempty"`
+	ThumbWidth           int                   `json:"thumb_width,omitempty"`
+	ThumbHeight          int                   `json:"thumb_height,omitempty"`
+}
+
+// InlineQueryResultVenue represents a venue.
+// See https://core.telegram.org/bots/api#inlinequeryresultvenue
+type InlineQueryResultVenue struct {
+	ID                  string                `json:"id"`
+	Latitude            float32               `json:"latitude"`
+	Longitude           float32               `json:"longitude"`
+	Title               string                `json:"title"`
+	Address             string                `json:"address"`
+	FoursquareID        string                `json:"foursquare_id,omitempty"`
+	FoursquareType      string                `json:"foursquare_type,omitempty"`
+	GooglePlaceID       string                `json:"google_place_id,omitempty"`
+	GooglePlaceType     string                `json:"google_place_type,omitempty"`
+	ReplyMarkup         *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
+	InputMessageContent InputMessageContent   `json:"input_message_content,omitempty"`
+	ThumbURL            string                `json:"thumb_url,omitempty"`
+	ThumbWidth          int                   `json:"thumb_width,omitempty"`
+	ThumbHeight         int                   `json:"thumb_height,omitempty"`
+}
+
+// InlineQueryResultContact represents a contact with a phone number.
+// See https://core.telegram.org/bots/api#inlinequeryresultcontact
+type InlineQueryResultContact struct {
+	ID                  string                `json:"id"`
+	PhoneNumber         string                `json:"phone_number"`
+	FirstName           string                `json:"first_name"`
+	LastName            string                `json:"last_name,omitempty"`
+	VCard               string                `json:"vcard,omitempty"`
+	ReplyMarkup         *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
+	InputMessageContent InputMessageContent   `json:"input_message_content,omitempty"`
+	ThumbURL            string                `json:"thumb_url,omitempty"`
+	ThumbWidth          int                   `json:"thumb_width,omitempty"`
+	ThumbHeight         int                   `json:"thumb_height,omitempty"`
+}
+
+// InlineQueryResultGame represents a game.
+// See https://core.telegram.org/bots/api#inlinequeryresultgame
+type InlineQueryResultGame struct {
+	ID            string                `json:"id"`
+	GameShortName string                `json:"game_short_name"`
+	ReplyMarkup   *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
+}
+
+// InlineQueryResultCachedPhoto represents a link to a photo stored on Telegram's servers.
+// See https://core.telegram.org/bots/api#inlinequeryresultcachedphoto
+type InlineQueryResultCachedPhoto struct {
+	ID                  string                `json:"id"`
+	PhotoFileID         string                `json:"photo_file_id"`
+	Title               string                `json:"title,omitempty"`
+	Description         string                `json:"description,omitempty"`
+	Caption             string                `json:"caption,omitempty"`
+	ParseMode           string                `json:"parse_mode,omitempty"`
+	CaptionEntities     []MessageEntity       `json:"caption_entities,omitempty"`
+	ReplyMarkup         *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
+	InputMessageContent InputMessageContent   `json:"input_message_content,omitempty"`
+}
+
+// InlineQueryResultCachedGif represents a link to an animated GIF file stored on Telegram's servers.
+// See https://core.telegram.org/bots/api#inlinequeryresultcachedgif
+type InlineQueryResultCachedGif struct {
+	ID                  string                `json:"id"`
+	GifFileID           string                `json:"gif_file_id"`
+	Title               string                `json:"title,omitempty"`
+	Caption             string                `json:"caption,omitempty"`
+	ParseMode           string                `json:"parse_mode,omitempty"`
+	CaptionEntities     []MessageEntity       `json:"caption_entities,omitempty"`
+	ReplyMarkup         *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
+	InputMessageContent InputMessageContent   `json:"input_message_content,omitempty"`
+}
+
+// InlineQueryResultCachedMpeg4Gif represents a link to a video animation stored on Telegram's servers.
+// See https://core.telegram.org/bots/api#inlinequeryresultcachedmpeg4gif
+type InlineQueryResultCachedMpeg4Gif struct {
+	ID                  string                `json:"id"`
+	Mpeg4FileID         string                `json:"mpeg4_file_id"`
+	Title               string                `json:"title,omitempty"`
+	Caption             string                `json:"caption,omitempty"`
+	ParseMode           string                `json:"parse_mode,omitempty"`
+	CaptionEntities     []MessageEntity       `json:"caption_entities,omitempty"`
+	ReplyMarkup         *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
+	InputMessageContent InputMessageContent   `json:"input_message_content,omitempty"`
+}
+
+// InlineQueryResultCachedSticker represents a link to a sticker stored on Telegram's servers.
+// See https://core.telegram.org/bots/api#inlinequeryresultcachedsticker
+type InlineQueryResultCachedSticker struct {
+	ID                  string                `json:"id"`
+	StickerFileID       string                `json:"sticker_file_id"`
+	ReplyMarkup         *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
+	InputMessageContent InputMessageContent   `json:"input_message_content,omitempty"`
+}
+
+// InlineQueryResultCachedDocument represents a link to a file stored on Telegram's servers.
+// See https://core.telegram.org/bots/api#inlinequeryresultcacheddocument
+type InlineQueryResultCachedDocument struct {
+	ID                  string                `json:"id"`
+	Title               string                `json:"title"`
+	DocumentFileID      string                `json:"document_file_id"`
+	Description         string                `json:"description,omitempty"`
+	Caption             string                `json:"caption,omitempty"`
+	ParseMode           string                `json:"parse_mode,omitempty"`
+	CaptionEntities     []MessageEntity       `json:"caption_entities,omitempty"`
+	ReplyMarkup         *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
+	InputMessageContent InputMessageContent   `json:"input_message_content,omitempty"`
+}
+
+// InlineQueryResultCachedVideo represents a link to a video file stored on Telegram's servers.
+// See https://core.telegram.org/bots/api#inlinequeryresultcachedvideo
+type InlineQueryResultCachedVideo struct {
+	ID                  string                `json:"id"`
+	VideoFileID         string                `json:"video_file_id"`
+	Title               string                `json:"title"`
+	Description         string                `json:"description,omitempty"`
+	Caption             string                `json:"caption,omitempty"`
+	ParseMode           string                `json:"parse_mode,omitempty"`
+	CaptionEntities     []MessageEntity       `json:"caption_entities,omitempty"`
+	ReplyMarkup         *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
+	InputMessageContent InputMessageContent   `json:"input_message_content,omitempty"`
+}
+
+// InlineQueryResultCachedVoice represents a link to a voice message stored on Telegram's servers.
+// See https://core.telegram.org/bots/api#inlinequeryresultcachedvoice
+type InlineQueryResultCachedVoice struct {
+	ID                  string                `json:"id"`
+	VoiceFileID         string                `json:"voice_file_id"`
+	Title               string                `json:"title"`
+	Caption             string                `json:"caption,omitempty"`
+	ParseMode           string                `json:"parse_mode,omitempty"`
+	CaptionEntities     []MessageEntity       `json:"caption_entities,omitempty"`
+	ReplyMarkup         *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
+	InputMessageContent InputMessageContent   `json:"input_message_content,omitempty"`
+}
+
+// InlineQueryResultCachedAudio represents a link to an MP3 audio file stored on Telegram's servers.
+// See https://core.telegram.org/bots/api#inlinequeryresultcachedaudio
+type InlineQueryResultCachedAudio struct {
+	ID                  string                `json:"id"`
+	AudioFileID         string                `json:"audio_file_id"`
+	Caption             string                `json:"caption,omitempty"`
+	ParseMode           string                `json:"parse_mode,omitempty"`
+	CaptionEntities     []MessageEntity       `json:"caption_entities,omitempty"`
+	ReplyMarkup         *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
+	InputMessageContent InputMessageContent   `json:"input_message_content,omitempty"`
+}
+
+func (r *InlineQueryResultArticle) resultType() string        { return inlineQueryResultTypeArticle }
+func (r *InlineQueryResultPhoto) resultType() string          { return inlineQueryResultTypePhoto }
+func (r *InlineQueryResultGif) resultType() string            { return inlineQueryResultTypeGif }
+func (r *InlineQueryResultMpeg4Gif) resultType() string       { return inlineQueryResultTypeMpeg4Gif }
+func (r *InlineQueryResultVideo) resultType() string          { return inlineQueryResultTypeVideo }
+func (r *InlineQueryResultAudio) resultType() string          { return inlineQueryResultTypeAudio }
+func (r *InlineQueryResultVoice) resultType() string          { return inlineQueryResultTypeVoice }
+func (r *InlineQueryResultDocument) resultType() string       { return inlineQueryResultTypeDocument }
+func (r *InlineQueryResultLocation) resultType() string       { return inlineQueryResultTypeLocation }
+func (r *InlineQueryResultVenue) resultType() string          { return inlineQueryResultTypeVenue }
+func (r *InlineQueryResultContact) resultType() string        { return inlineQueryResultTypeContact }
+func (r *InlineQueryResultGame) resultType() string           { return inlineQueryResultTypeGame }
+func (r *InlineQueryResultCachedPhoto) resultType() string    { return inlineQueryResultTypePhoto }
+func (r *InlineQueryResultCachedGif) resultType() string      { return inlineQueryResultTypeGif }
+func (r *InlineQueryResultCachedMpeg4Gif) resultType() string { return inlineQueryResultTypeMpeg4Gif }
+func (r *InlineQueryResultCachedSticker) resultType() string  { return inlineQueryResultTypeSticker }
+func (r *InlineQueryResultCachedDocument) resultType() string { return inlineQueryResultTypeDocument }
+func (r *InlineQueryResultCachedVideo) resultType() string    { return inlineQueryResultTypeVideo }
+func (r *InlineQueryResultCachedVoice) resultType() string    { return inlineQueryResultTypeVoice }
+func (r *InlineQueryResultCachedAudio) resultType() string    { return inlineQueryResultTypeAudio }
+
+// InputTextMessageContent represents the content of a text message.
+// See https://core.telegram.org/bots/api#inputtextmessagecontent
+type InputTextMessageContent struct {
+	MessageText           string          `json:"message_text"`
+	ParseMode             string          `json:"parse_mode,omitempty"`
+	Entities              []MessageEntity `json:"entities,omitempty"`
+	DisableWebPagePreview bool            `json:"disable_web_page_preview,omitempty"`
+}
+
+// InputLocationMessageContent represents the content of a location message.
+// See https://core.telegram.org/bots/api#inputlocationmessagecontent
+type InputLocationMessageContent struct {
+	Latitude             float32 `json:"latitude"`
+	Longitude            float32 `json:"longitude"`
+	HorizontalAccuracy   float32 `json:"horizontal_accuracy,omitempty"`
+	LivePeriod           int     `json:"live_period,omitempty"`
+	Heading              int     `json:"heading,omitempty"`
+	ProximityAlertRadius int     `json:"proximity_alert_radius,omitempty"`
+}
+
+// InputVenueMessageContent represents the content of a venue message.
+// See https://core.telegram.org/bots/api#inputvenuemessagecontent
+type InputVenueMessageContent struct {
+	Latitude        float32 `json:"latitude"`
+	Longitude       float32 `json:"longitude"`
+	Title           string  `json:"title"`
+	Address         string  `json:"address"`
+	FoursquareID    string  `json:"foursquare_id,omitempty"`
+	FoursquareType  string  `json:"foursquare_type,omitempty"`
+	GooglePlaceID   string  `json:"google_place_id,omitempty"`
+	GooglePlaceType string  `json:"google_place_type,omitempty"`
+}
+
+// InputContactMessageContent represents the content of a contact message.
+// See https://core.telegram.org/bots/api#inputcontactmessagecontent
+type InputContactMessageContent struct {
+	PhoneNumber string `json:"phone_number"`
+	FirstName   string `json:"first_name"`
+	LastName    string `json:"last_name,omitempty"`
+	VCard       string `json:"vcard,omitempty"`
+}
+
+func (c *InputTextMessageContent) inputMessageContent()     {}
+func (c *InputLocationMessageContent) inputMessageContent() {}
+func (c *InputVenueMessageContent) inputMessageContent()    {}
+func (c *InputContactMessageContent) inputMessageContent()  {}
@@ -0,0 +1,41 @@
+package telegram // import "heytobi.dev/fuse/telegram"
+
+import (
+	"context"
+)
+
+type inlineQueryService struct {
+	executor *executor
+}
+
+func newInlineQueryService(executor *executor) (*inlineQueryService, error) {
+	return &inlineQueryService{
+		executor: executor,
+	}, nil
+}
+
+// answerInlineQuery sends the results answering an inline query.
+// See https://core.telegram.org/bots/api#answerinlinequery
+func (s *inlineQueryService) answerInlineQuery(ctx context.Context, answer *AnswerInlineQueryRequest) (bool, error) {
+	if answer == nil {
+		return false, errNilInlineQueryAnswer
+	}
+
+	if len(answer.Results) > maxInlineQueryResults {
+		return false, errTooManyInlineResults
+	}
+
+	for _, result := range answer.Results {
+		if isNil(result) {
+			return false, errNilInlineQueryResult
+		}
+	}
+
+	var answered bool
+	err := s.executor.execute(ctx, endpointAnswerInlineQuery, answer, &answered)
+	if err != nil {
+		return false, err
+	}
+
+	return answered, nil
+}
@@ -0,0 +1,99 @@
+package telegram
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAnswerInlineQuery_ReturnErrorIfAnswerIsNil(t *testing.T) {
+	service, _ := newInlineQueryService(newExecutor(&mockHttpClient{}, testApiUrlFmt, testToken, nil))
+
+	result, err := service.answerInlineQuery(context.Background(), nil)
+
+	assert.False(t, result)
+	assert.Equal(t, errNilInlineQueryAnswer, err)
+}
+
+func TestAnswerInlineQuery_ReturnErrorIfThereAreTooManyResults(t *testing.T) {
+	service, _ := newInlineQueryService(newExecutor(&mockHttpClient{}, testApiUrlFmt, testToken, nil))
+
+	results := make([]InlineQueryResult, 51)
+	for i := range results {
+		results[i] = &InlineQueryResultCachedSticker{ID: "sticker", StickerFileID: "sticker"}
+	}
+	result, err := service.answerInlineQuery(context.Background(), &AnswerInlineQueryRequest{
+		InlineQueryID: "query",
+		Results:       results,
+	})
+
+	assert.False(t, result)
+	assert.Equal(t, errTooManyInlineResults, err)
+}
+
+func TestAnswerInlineQuery_ReturnErrorIfResultIsNil(t *testing.T) {
+	service, _ := newInlineQueryService(newExecutor(&mockHttpClient{}, testApiUrlFmt, testToken, nil))
+
+	_, err := service.answerInlineQuery(context.Background(), &AnswerInlineQueryRequest{
+		InlineQueryID: "query",
+		Results:       []InlineQueryResult{nil},
+	})
+
+	assert.Equal(t, errNilInlineQueryResult, err)
+
+	_, err = service.answerInlineQuery(context.Background(), &AnswerInlineQueryRequest{
+		InlineQueryID: "query",
+		Results:       []InlineQueryResult{(*InlineQueryResultArticle)(nil)},
+	})
+
+	assert.Equal(t, errNilInlineQueryResult, err)
+}
+
+func TestAnswerInlineQuery_SendTypedResults(t *testing.T) {
+	httpClient, requests := newRecordingHttpClient(`{"ok": true, "result": true}`)
+	service, _ := newInlineQueryService(newExecutor(httpClient, testApiUrlFmt, testToken, nil))
+
+	result, err := service.answerInlineQuery(context.Background(), &AnswerInlineQueryRequest{
+		InlineQueryID: "query",
+		Results: []InlineQueryResult{
+			&InlineQueryResultArticle{
+				ID:                  "1",
+				Title:               "Hello",
+				InputMessageContent: &InputTextMessageContent{MessageText: "hello", ParseMode: "HTML"},
+			},
+			&InlineQueryResultCachedPhoto{ID: "2", PhotoFileID: "photo"},
+			&InlineQueryResultVenue{
+				ID:        "3",
+				Latitude:  1.5,
+				Longitude: 2.5,
+				Title:     "Venue",
+				Address:   "Street",
+			},
+		},
+		NextOffset: "3",
+	})
+
+	assert.True(t, result)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{
+		"inline_query_id": "query",
+		"results": [
+			{"type": "article", "id": "1", "title": "Hello",
+				"input_message_content": {"message_text": "hello", "parse_mode": "HTML"}},
+			{"type": "photo", "id": "2", "photo_file_id": "photo"},
+			{"type": "venue", "id": "3", "latitude": 1.5, "longitude": 2.5, "title": "Venue", "address": "Street"}
+		],
+		"next_offset": "3"
+	}`, requests[endpointAnswerInlineQuery][0])
+}
+
+func TestAnswerInlineQuery_SendEmptyResults(t *testing.T) {
+	httpClient, requests := newRecordingHttpClient(`{"ok": true, "result": true}`)
+	service, _ := newInlineQueryService(newExecutor(httpClient, testApiUrlFmt, testToken, nil))
+
+	_, err := service.answerInlineQuery(context.Background(), &AnswerInlineQueryRequest{InlineQueryID: "query"})
+
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"inline_query_id": "query", "results": []}`, requests[endpointAnswerInlineQuery][0])
+}
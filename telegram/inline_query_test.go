@@ -0,0 +1,61 @@
+package telegram
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegisterInlineQueryHandler_AnswerInlineQuery(t *testing.T) {
+	httpClient, requests := newRecordingHttpClient(`{"ok": true, "result": true}`)
+	bot, _ := NewBot(&Config{Token: "test"}, httpClient)
+	_ = bot.RegisterInlineQueryHandler(func(ctx context.Context, query *InlineQuery) (*AnswerInlineQueryRequest, error) {
+		return &AnswerInlineQueryRequest{
+			Results: []InlineQueryResult{
+				&InlineQueryResultArticle{
+					ID:                  "1",
+					Title:               query.Query,
+					InputMessageContent: &InputTextMessageContent{MessageText: query.Query},
+				},
+			},
+			NextOffset: "1",
+		}, nil
+	})
+
+	err := bot.ProcessUpdate(context.Background(), &Update{InlineQuery: &InlineQuery{ID: "query", Query: "cats"}})
+
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{
+		"inline_query_id": "query",
+		"results": [
+			{"type": "article", "id": "1", "title": "cats", "input_message_content": {"message_text": "cats"}}
+		],
+		"next_offset": "1"
+	}`, requests[endpointAnswerInlineQuery][0])
+}
+
+func TestRegisterInlineQueryHandler_DontAnswerIfHandlerFails(t *testing.T) {
+	httpClient, requests := newRecordingHttpClient(`{"ok": true, "result": true}`)
+	bot, _ := NewBot(&Config{Token: "test"}, httpClient)
+	_ = bot.RegisterInlineQueryHandler(func(ctx context.Context, query *InlineQuery) (*AnswerInlineQueryRequest, error) {
+		return &AnswerInlineQueryRequest{}, errors.New("error")
+	})
+
+	err := bot.ProcessUpdate(context.Background(), &Update{InlineQuery: &InlineQuery{ID: "query"}})
+
+	assert.Error(t, err)
+	assert.Empty(t, requests[endpointAnswerInlineQuery])
+}
+
+func TestRegisterInlineQueryHandler_ReturnErrorIfInlineQueryHandlerExists(t *testing.T) {
+	bot, _ := NewBot(&Config{Token: "test"}, &mockHttpClient{})
+	_ = bot.OnInlineQuery(func(ctx context.Context, update *Update) error { return nil })
+
+	err := bot.RegisterInlineQueryHandler(
+		func(ctx context.Context, query *InlineQuery) (*AnswerInlineQueryRequest, error) { return nil, nil },
+	)
+
+	assert.Equal(t, errUpdateHandlerExists, err)
+}
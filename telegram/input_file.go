@@ -0,0 +1,79 @@
+package telegram // import "heytobi.dev/fuse/telegram"
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// InputFile represents a file to be sent, either a file already stored on Telegram's servers, a file Telegram has to
+// download from a URL, or a file to upload. Uploaded files are streamed as multipart/form-data, they are not loaded
+// in memory.
+// See https://core.telegram.org/bots/api#sending-files
+type InputFile struct {
+	fileID string
+	url    string
+	name   string
+	reader io.Reader
+	path   string
+}
+
+// FileID returns an InputFile referencing a file already stored on Telegram's servers.
+func FileID(fileID string) *InputFile {
+	return &InputFile{fileID: fileID}
+}
+
+// FileURL returns an InputFile that Telegram downloads from the given HTTP URL.
+func FileURL(url string) *InputFile {
+	return &InputFile{url: url}
+}
+
+// FileFromReader returns an InputFile uploading the content of the given reader, with the given file name. Since the
+// reader can only be consumed once, requests uploading it are never retried.
+func FileFromReader(name string, reader io.Reader) *InputFile {
+	return &InputFile{name: name, reader: reader}
+}
+
+// FileFromPath returns an InputFile uploading the local file at the given path.
+func FileFromPath(path string) *InputFile {
+	return &InputFile{name: filepath.Base(path), path: path}
+}
+
+// MarshalJSON encodes files that don't need to be uploaded as their ID or URL. Files that need to be uploaded are
+// sent as parts of a multipart/form-data request instead.
+func (f *InputFile) MarshalJSON() ([]byte, error) {
+	if f.fileID != "" {
+		return json.Marshal(f.fileID)
+	}
+
+	if f.url != "" {
+		return json.Marshal(f.url)
+	}
+
+	return []byte("null"), nil
+}
+
+func (f *InputFile) needsUpload() bool {
+	return f.reader != nil || f.path != ""
+}
+
+// isReplayable returns whether the file can be uploaded again, if a request uploading it has to be retried.
+func (f *InputFile) isReplayable() bool {
+	return f.reader == nil
+}
+
+// open returns the content of a file to upload, the returned reader must be closed by the caller.
+func (f *InputFile) open() (io.ReadCloser, error) {
+	if f.path != "" {
+		return os.Open(f.path)
+	}
+
+	return io.NopCloser(f.reader), nil
+}
+
+// uploader is implemented by requests that can send files.
+type uploader interface {
+	// files returns the files of the request by the name of the parameter they are sent as.
+	files() map[string]*InputFile
+}
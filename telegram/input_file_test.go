@@ -0,0 +1,181 @@
+package telegram
+
+import (
+	"context"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSendPhoto_SendFileIDAsJson(t *testing.T) {
+	httpClient, requests := newRecordingHttpClient(`{"ok": true, "result": {"message_id": 1}}`)
+	service, _ := newMessagingService(newExecutor(httpClient, testApiUrlFmt, testToken, nil))
+
+	_, err := service.sendPhoto(context.Background(), &SendPhotoRequest{ChatID: 7, Photo: FileID("file-id")})
+
+	assert.NoError(t, err)
+	assert.Equal(t, `{"chat_id":7,"photo":"file-id"}`, requests[endpointSendPhoto][0])
+}
+
+func TestSendDocument_SendUrlAsJson(t *testing.T) {
+	httpClient, requests := newRecordingHttpClient(`{"ok": true, "result": {"message_id": 1}}`)
+	service, _ := newMessagingService(newExecutor(httpClient, testApiUrlFmt, testToken, nil))
+
+	_, err := service.sendDocument(context.Background(), &SendDocumentRequest{
+		ChatID:   7,
+		Document: FileURL("https://example.com/file.pdf"),
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, `{"chat_id":7,"document":"https://example.com/file.pdf"}`, requests[endpointSendDocument][0])
+}
+
+func TestSendPhoto_UploadReaderAsMultipart(t *testing.T) {
+	var form *multipart.Form
+	httpClient := httpClientFunc(func(request *http.Request) (*http.Response, error) {
+		form = readMultipartForm(t, request)
+		return newResponse(http.StatusOK, `{"ok": true, "result": {"message_id": 1}}`), nil
+	})
+	service, _ := newMessagingService(newExecutor(httpClient, testApiUrlFmt, testToken, nil))
+
+	message, err := service.sendPhoto(context.Background(), &SendPhotoRequest{
+		ChatID:          7,
+		Photo:           FileFromReader("cat.jpg", strings.NewReader("meow")),
+		Caption:         "cat",
+		CaptionEntities: []MessageEntity{{Type: "bold", Length: 3}},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), message.ID)
+	assert.Equal(t, []string{"7"}, form.Value["chat_id"])
+	assert.Equal(t, []string{"cat"}, form.Value["caption"])
+	assert.Equal(t, []string{`[{"type":"bold","offset":0,"length":3,"url":"","user":null,"language":""}]`},
+		form.Value["caption_entities"])
+	assert.NotContains(t, form.Value, "photo")
+	assert.Equal(t, "cat.jpg", form.File["photo"][0].Filename)
+	assert.Equal(t, "meow", readFormFile(t, form.File["photo"][0]))
+}
+
+func TestSendVideo_UploadPathAndThumbAsMultipart(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "video.mp4")
+	assert.NoError(t, os.WriteFile(path, []byte("video"), 0o600))
+
+	var form *multipart.Form
+	httpClient := httpClientFunc(func(request *http.Request) (*http.Response, error) {
+		form = readMultipartForm(t, request)
+		return newResponse(http.StatusOK, `{"ok": true, "result": {"message_id": 1}}`), nil
+	})
+	service, _ := newMessagingService(newExecutor(httpClient, testApiUrlFmt, testToken, nil))
+
+	_, err := service.sendVideo(context.Background(), &SendVideoRequest{
+		ChatID:            7,
+		Video:             FileFromPath(path),
+		Thumb:             FileFromReader("thumb.jpg", strings.NewReader("thumb")),
+		SupportsStreaming: true,
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"true"}, form.Value["supports_streaming"])
+	assert.Equal(t, "video.mp4", form.File["video"][0].Filename)
+	assert.Equal(t, "video", readFormFile(t, form.File["video"][0]))
+	assert.Equal(t, "thumb", readFormFile(t, form.File["thumb"][0]))
+}
+
+func TestSendVoice_ReturnErrorIfFileIsMissing(t *testing.T) {
+	service, _ := newMessagingService(newExecutor(&mockHttpClient{}, testApiUrlFmt, testToken, nil))
+
+	message, err := service.sendVoice(context.Background(), &SendVoiceRequest{ChatID: 7})
+
+	assert.Nil(t, message)
+	assert.Equal(t, errMissingFile, err)
+}
+
+func TestSendAudio_ReturnErrorIfFileCannotBeOpened(t *testing.T) {
+	httpClient := httpClientFunc(func(request *http.Request) (*http.Response, error) {
+		_, err := io.ReadAll(request.Body)
+		return nil, err
+	})
+	service, _ := newMessagingService(newExecutor(httpClient, testApiUrlFmt, testToken, nil))
+
+	_, err := service.sendAudio(context.Background(), &SendAudioRequest{
+		ChatID: 7,
+		Audio:  FileFromPath(filepath.Join(t.TempDir(), "missing.mp3")),
+	})
+
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestExecute_RetryUploadFromPath(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "animation.gif")
+	assert.NoError(t, os.WriteFile(path, []byte("gif"), 0o600))
+
+	var calls atomic.Int32
+	httpClient := httpClientFunc(func(request *http.Request) (*http.Response, error) {
+		form := readMultipartForm(t, request)
+		assert.Equal(t, "gif", readFormFile(t, form.File["animation"][0]))
+
+		if calls.Add(1) == 1 {
+			return newResponse(http.StatusTooManyRequests, `{"ok": false, "error_code": 429}`), nil
+		}
+		return newResponse(http.StatusOK, `{"ok": true, "result": {"message_id": 1}}`), nil
+	})
+	executor := newExecutor(httpClient, testApiUrlFmt, testToken, testRetryPolicy)
+
+	err := executor.execute(
+		context.Background(),
+		endpointSendAnimation,
+		&SendAnimationRequest{ChatID: 7, Animation: FileFromPath(path)},
+		nil,
+	)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int32(2), calls.Load())
+}
+
+func TestExecute_DoNotRetryUploadFromReader(t *testing.T) {
+	var calls atomic.Int32
+	httpClient := httpClientFunc(func(request *http.Request) (*http.Response, error) {
+		calls.Add(1)
+		return newResponse(http.StatusTooManyRequests, `{"ok": false, "error_code": 429}`), nil
+	})
+	executor := newExecutor(httpClient, testApiUrlFmt, testToken, testRetryPolicy)
+
+	err := executor.execute(
+		context.Background(),
+		endpointSendPhoto,
+		&SendPhotoRequest{ChatID: 7, Photo: FileFromReader("cat.jpg", strings.NewReader("meow"))},
+		nil,
+	)
+
+	assert.Error(t, err)
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func readMultipartForm(t *testing.T, request *http.Request) *multipart.Form {
+	_, params, err := mime.ParseMediaType(request.Header.Get("Content-Type"))
+	assert.NoError(t, err)
+
+	form, err := multipart.NewReader(request.Body, params["boundary"]).ReadForm(1 << 20)
+	assert.NoError(t, err)
+
+	return form
+}
+
+func readFormFile(t *testing.T, header *multipart.FileHeader) string {
+	file, err := header.Open()
+	assert.NoError(t, err)
+	defer file.Close()
+
+	content, err := io.ReadAll(file)
+	assert.NoError(t, err)
+
+	return string(content)
+}
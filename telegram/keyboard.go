@@ -32,3 +32,13 @@ type LoginUrl struct {
 // See https://core.telegram.org/bots/api#callbackgame
 type CallbackGame struct {
 }
+
+// AnswerCallbackQueryRequest defines an answer to a callback query sent from an inline keyboard.
+// See https://core.telegram.org/bots/api#answercallbackquery
+type AnswerCallbackQueryRequest struct {
+	CallbackQueryID string `json:"callback_query_id"`
+	Text            string `json:"text,omitempty"`
+	ShowAlert       bool   `json:"show_alert,omitempty"`
+	URL             string `json:"url,omitempty"`
+	CacheTime       int    `json:"cache_time,omitempty"`
+}
@@ -99,3 +99,140 @@ type Sticker struct {
 	MaskPosition *MaskPosition `json:"mask_position"`
 	FileSize     int64         `json:"file_size"`
 }
+
+// SendPhotoRequest defines a photo to be sent by the bot.
+// See https://core.telegram.org/bots/api#sendphoto
+type SendPhotoRequest struct {
+	ChatID                   int64           `json:"chat_id"`
+	Photo                    *InputFile      `json:"photo"`
+	Caption                  string          `json:"caption,omitempty"`
+	ParseMode                string          `json:"parse_mode,omitempty"`
+	CaptionEntities          []MessageEntity `json:"caption_entities,omitempty"`
+	DisableNotification      bool            `json:"disable_notification,omitempty"`
+	ProtectContent           bool            `json:"protect_content,omitempty"`
+	ReplyToMessageID         int64           `json:"reply_to_message_id,omitempty"`
+	AllowSendingWithoutReply bool            `json:"allow_sending_without_reply,omitempty"`
+	ReplyMarkup              any             `json:"reply_markup,omitempty"`
+}
+
+// SendDocumentRequest defines a general file to be sent by the bot.
+// See https://core.telegram.org/bots/api#senddocument
+type SendDocumentRequest struct {
+	ChatID                      int64           `json:"chat_id"`
+	Document                    *InputFile      `json:"document"`
+	Thumb                       *InputFile      `json:"thumb,omitempty"`
+	Caption                     string          `json:"caption,omitempty"`
+	ParseMode                   string          `json:"parse_mode,omitempty"`
+	CaptionEntities             []MessageEntity `json:"caption_entities,omitempty"`
+	DisableContentTypeDetection bool            `json:"disable_content_type_detection,omitempty"`
+	DisableNotification         bool            `json:"disable_notification,omitempty"`
+	ProtectContent              bool            `json:"protect_content,omitempty"`
+	ReplyToMessageID            int64           `json:"reply_to_message_id,omitempty"`
+	AllowSendingWithoutReply    bool            `json:"allow_sending_without_reply,omitempty"`
+	ReplyMarkup                 any             `json:"reply_markup,omitempty"`
+}
+
+// SendAudioRequest defines an audio file to be sent by the bot, to be displayed in the music player.
+// See https://core.telegram.org/bots/api#sendaudio
+type SendAudioRequest struct {
+	ChatID                   int64           `json:"chat_id"`
+	Audio                    *InputFile      `json:"audio"`
+	Caption                  string          `json:"caption,omitempty"`
+	ParseMode                string          `json:"parse_mode,omitempty"`
+	CaptionEntities          []MessageEntity `json:"caption_entities,omitempty"`
+	Duration                 int             `json:"duration,omitempty"`
+	Performer                string          `json:"performer,omitempty"`
+	Title                    string          `json:"title,omitempty"`
+	Thumb                    *InputFile      `json:"thumb,omitempty"`
+	DisableNotification      bool            `json:"disable_notification,omitempty"`
+	ProtectContent           bool            `json:"protect_content,omitempty"`
+	ReplyToMessageID         int64           `json:"reply_to_message_id,omitempty"`
+	AllowSendingWithoutReply bool            `json:"allow_sending_without_reply,omitempty"`
+	ReplyMarkup              any             `json:"reply_markup,omitempty"`
+}
+
+// SendVideoRequest defines a video to be sent by the bot.
+// See https://core.telegram.org/bots/api#sendvideo
+type SendVideoRequest struct {
+	ChatID                   int64           `json:"chat_id"`
+	Video                    *InputFile      `json:"video"`
+	Duration                 int             `json:"duration,omitempty"`
+	Width                    int             `json:"width,omitempty"`
+	Height                   int             `json:"height,omitempty"`
+	Thumb                    *InputFile      `json:"thumb,omitempty"`
+	Caption                  string          `json:"caption,omitempty"`
+	ParseMode                string          `json:"parse_mode,omitempty"`
+	CaptionEntities          []MessageEntity `json:"caption_entities,omitempty"`
+	SupportsStreaming        bool            `json:"supports_streaming,omitempty"`
+	DisableNotification      bool            `json:"disable_notification,omitempty"`
+	ProtectContent           bool            `json:"protect_content,omitempty"`
+	ReplyToMessageID         int64           `json:"reply_to_message_id,omitempty"`
+	AllowSendingWithoutReply bool            `json:"allow_sending_without_reply,omitempty"`
+	ReplyMarkup              any             `json:"reply_markup,omitempty"`
+}
+
+// SendVoiceRequest defines a voice note to be sent by the bot.
+// See https://core.telegram.org/bots/api#sendvoice
+type SendVoiceRequest struct {
+	ChatID                   int64           `json:"chat_id"`
+	Voice                    *InputFile      `json:"voice"`
+	Caption                  string          `json:"caption,omitempty"`
+	ParseMode                string          `json:"parse_mode,omitempty"`
+	CaptionEntities          []MessageEntity `json:"caption_entities,omitempty"`
+	Duration                 int             `json:"duration,omitempty"`
+	DisableNotification      bool            `json:"disable_notification,omitempty"`
+	ProtectContent           bool            `json:"protect_content,omitempty"`
+	ReplyToMessageID         int64           `json:"reply_to_message_id,omitempty"`
+	AllowSendingWithoutReply bool            `json:"allow_sending_without_reply,omitempty"`
+	ReplyMarkup              any             `json:"reply_markup,omitempty"`
+}
+
+// SendAnimationRequest defines an animation, i.e. a GIF or a video without sound, to be sent by the bot.
+// See https://core.telegram.org/bots/api#sendanimation
+type SendAnimationRequest struct {
+	ChatID                   int64           `json:"chat_id"`
+	Animation                *InputFile      `json:"animation"`
+	Duration                 int             `json:"duration,omitempty"`
+	Width                    int             `json:"width,omitempty"`
+	Height                   int             `json:"height,omitempty"`
+	Thumb                    *InputFile      `json:"thumb,omitempty"`
+	Caption                  string          `json:"caption,omitempty"`
+	ParseMode                string          `json:"parse_mode,omitempty"`
+	CaptionEntities          []MessageEntity `json:"caption_entities,omitempty"`
+	DisableNotification      bool            `json:"disable_notification,omitempty"`
+	ProtectContent           bool            `json:"protect_content,omitempty"`
+	ReplyToMessageID         int64           `json:"reply_to_message_id,omitempty"`
+	AllowSendingWithoutReply bool            `json:"allow_sending_without_reply,omitempty"`
+	ReplyMarkup              any             `json:"reply_markup,omitempty"`
+}
+
+func (r *SendPhotoRequest) targetChatID() int64     { return r.ChatID }
+func (r *SendDocumentRequest) targetChatID() int64  { return r.ChatID }
+func (r *SendAudioRequest) targetChatID() int64     { return r.ChatID }
+func (r *SendVideoRequest) targetChatID() int64     { return r.ChatID }
+func (r *SendVoiceRequest) targetChatID() int64     { return r.ChatID }
+func (r *SendAnimationRequest) targetChatID() int64 { return r.ChatID }
+
+func (r *SendPhotoRequest) files() map[string]*InputFile {
+	return map[string]*InputFile{"photo": r.Photo}
+}
+
+func (r *SendDocumentRequest) files() map[string]*InputFile {
+	return map[string]*InputFile{"document": r.Document, "thumb": r.Thumb}
+}
+
+func (r *SendAudioRequest) files() map[string]*InputFile {
+	return map[string]*InputFile{"audio": r.Audio, "thumb": r.Thumb}
+}
+
+func (r *SendVideoRequest) files() map[string]*InputFile {
+	return map[string]*InputFile{"video": r.Video, "thumb": r.Thumb}
+}
+
+func (r *SendVoiceRequest) files() map[string]*InputFile {
+	return map[string]*InputFile{"voice": r.Voice}
+}
+
+func (r *SendAnimationRequest) files() map[string]*InputFile {
+	return map[string]*InputFile{"animation": r.Animation, "thumb": r.Thumb}
+}
@@ -0,0 +1,245 @@
+package telegram // import "heytobi.dev/fuse/telegram"
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+const (
+	mediaTypePhoto     = "photo"
+	mediaTypeVideo     = "video"
+	mediaTypeAnimation = "animation"
+	mediaTypeAudio     = "audio"
+	mediaTypeDocument  = "document"
+
+	minMediaGroupSize = 2
+	maxMediaGroupSize = 10
+)
+
+// InputMedia is an item of a media group or the new media of an edited message, i.e. an InputMediaPhoto, an
+// InputMediaVideo, an InputMediaAnimation, an InputMediaAudio or an InputMediaDocument.
+// See https://core.telegram.org/bots/api#inputmedia
+type InputMedia interface {
+	mediaFile() *InputFile
+	thumbFile() *InputFile
+}
+
+// InputMediaPhoto represents a photo to be sent in a media group.
+// See https://core.telegram.org/bots/api#inputmediaphoto
+type InputMediaPhoto struct {
+	Media           *InputFile      `json:"media"`
+	Caption         string          `json:"caption,omitempty"`
+	ParseMode       string          `json:"parse_mode,omitempty"`
+	CaptionEntities []MessageEntity `json:"caption_entities,omitempty"`
+}
+
+// InputMediaVideo represents a video to be sent in a media group.
+// See https://core.telegram.org/bots/api#inputmediavideo
+type InputMediaVideo struct {
+	Media             *InputFile      `json:"media"`
+	Thumb             *InputFile      `json:"thumb,omitempty"`
+	Caption           string          `json:"caption,omitempty"`
+	ParseMode         string          `json:"parse_mode,omitempty"`
+	CaptionEntities   []MessageEntity `json:"caption_entities,omitempty"`
+	Width             int             `json:"width,omitempty"`
+	Height            int             `json:"height,omitempty"`
+	Duration          int             `json:"duration,omitempty"`
+	SupportsStreaming bool            `json:"supports_streaming,omitempty"`
+}
+
+// InputMediaAnimation represents an animation, i.e. a GIF or a video without sound, replacing the media of a message
+// through EditMessageMedia. Animations can't be sent in media groups.
+// See https://core.telegram.org/bots/api#inputmediaanimation
+type InputMediaAnimation struct {
+	Media           *InputFile      `json:"media"`
+	Thumb           *InputFile      `json:"thumb,omitempty"`
+	Caption         string          `json:"caption,omitempty"`
+	ParseMode       string          `json:"parse_mode,omitempty"`
+	CaptionEntities []MessageEntity `json:"caption_entities,omitempty"`
+	Width           int             `json:"width,omitempty"`
+	Height          int             `json:"height,omitempty"`
+	Duration        int             `json:"duration,omitempty"`
+}
+
+// InputMediaAudio represents an audio file to be sent in a media group.
+// See https://core.telegram.org/bots/api#inputmediaaudio
+type InputMediaAudio struct {
+	Media           *InputFile      `json:"media"`
+	Thumb           *InputFile      `json:"thumb,omitempty"`
+	Caption         string          `json:"caption,omitempty"`
+	ParseMode       string          `json:"parse_mode,omitempty"`
+	CaptionEntities []MessageEntity `json:"caption_entities,omitempty"`
+	Duration        int             `json:"duration,omitempty"`
+	Performer       string          `json:"performer,omitempty"`
+	Title           string          `json:"title,omitempty"`
+}
+
+// InputMediaDocument represents a general file to be sent in a media group.
+// See https://core.telegram.org/bots/api#inputmediadocument
+type InputMediaDocument struct {
+	Media                       *InputFile      `json:"media"`
+	Thumb                       *InputFile      `json:"thumb,omitempty"`
+	Caption                     string          `json:"caption,omitempty"`
+	ParseMode                   string          `json:"parse_mode,omitempty"`
+	CaptionEntities             []MessageEntity `json:"caption_entities,omitempty"`
+	DisableContentTypeDetection bool            `json:"disable_content_type_detection,omitempty"`
+}
+
+func (m *InputMediaPhoto) mediaFile() *InputFile     { return m.Media }
+func (m *InputMediaVideo) mediaFile() *InputFile     { return m.Media }
+func (m *InputMediaAnimation) mediaFile() *InputFile { return m.Media }
+func (m *InputMediaAudio) mediaFile() *InputFile     { return m.Media }
+func (m *InputMediaDocument) mediaFile() *InputFile  { return m.Media }
+
+func (m *InputMediaPhoto) thumbFile() *InputFile     { return nil }
+func (m *InputMediaVideo) thumbFile() *InputFile     { return m.Thumb }
+func (m *InputMediaAnimation) thumbFile() *InputFile { return m.Thumb }
+func (m *InputMediaAudio) thumbFile() *InputFile     { return m.Thumb }
+func (m *InputMediaDocument) thumbFile() *InputFile  { return m.Thumb }
+
+// MarshalJSON adds the type of the media, as expected by the Bot API.
+func (m *InputMediaPhoto) MarshalJSON() ([]byte, error) {
+	type inputMedia InputMediaPhoto
+	return marshalWithType(mediaTypePhoto, (*inputMedia)(m))
+}
+
+// MarshalJSON adds the type of the media, as expected by the Bot API.
+func (m *InputMediaVideo) MarshalJSON() ([]byte, error) {
+	type inputMedia InputMediaVideo
+	return marshalWithType(mediaTypeVideo, (*inputMedia)(m))
+}
+
+// MarshalJSON adds the type of the media, as expected by the Bot API.
+func (m *InputMediaAnimation) MarshalJSON() ([]byte, error) {
+	type inputMedia InputMediaAnimation
+	return marshalWithType(mediaTypeAnimation, (*inputMedia)(m))
+}
+
+// MarshalJSON adds the type of the media, as expected by the Bot API.
+func (m *InputMediaAudio) MarshalJSON() ([]byte, error) {
+	type inputMedia InputMediaAudio
+	return marshalWithType(mediaTypeAudio, (*inputMedia)(m))
+}
+
+// MarshalJSON adds the type of the media, as expected by the Bot API.
+func (m *InputMediaDocument) MarshalJSON() ([]byte, error) {
+	type inputMedia InputMediaDocument
+	return marshalWithType(mediaTypeDocument, (*inputMedia)(m))
+}
+
+// marshalWithType encodes the given value as a JSON object with an additional type field, used by the Bot API to tell
+// apart the variants of InputMedia or InlineQueryResult.
+func marshalWithType(typ string, value any) ([]byte, error) {
+	fields, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	var object map[string]json.RawMessage
+	err = json.Unmarshal(fields, &object)
+	if err != nil {
+		return nil, err
+	}
+
+	// a nil pointer is encoded as null, which leaves the object nil.
+	if object == nil {
+		object = make(map[string]json.RawMessage)
+	}
+
+	object["type"], _ = json.Marshal(typ)
+
+	return json.Marshal(object)
+}
+
+// isNil checks if the given interface value is nil or holds a nil pointer, e.g. a (*InputMediaPhoto)(nil) passed as
+// an InputMedia.
+func isNil(value any) bool {
+	if value == nil {
+		return true
+	}
+
+	v := reflect.ValueOf(value)
+
+	return v.Kind() == reflect.Pointer && v.IsNil()
+}
+
+// SendMediaGroupRequest defines an album of 2 to 10 photos, videos, audio files or documents to be sent by the bot.
+// Documents and audio files can only be grouped with media of the same type.
+// See https://core.telegram.org/bots/api#sendmediagroup
+type SendMediaGroupRequest struct {
+	ChatID                   int64        `json:"chat_id"`
+	Media                    []InputMedia `json:"media"`
+	DisableNotification      bool         `json:"disable_notification,omitempty"`
+	ProtectContent           bool         `json:"protect_content,omitempty"`
+	ReplyToMessageID         int64        `json:"reply_to_message_id,omitempty"`
+	AllowSendingWithoutReply bool         `json:"allow_sending_without_reply,omitempty"`
+}
+
+// MarshalJSON references the files to upload by the name of the multipart/form-data part they are uploaded as.
+func (r *SendMediaGroupRequest) MarshalJSON() ([]byte, error) {
+	media := make([]json.RawMessage, len(r.Media))
+	for i, item := range r.Media {
+		var err error
+		media[i], err = marshalMediaItem(item, i)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	type sendMediaGroupRequest SendMediaGroupRequest
+	return json.Marshal(&struct {
+		*sendMediaGroupRequest
+		Media []json.RawMessage `json:"media"`
+	}{
+		sendMediaGroupRequest: (*sendMediaGroupRequest)(r),
+		Media:                 media,
+	})
+}
+
+func (r *SendMediaGroupRequest) targetChatID() int64 {
+	return r.ChatID
+}
+
+func (r *SendMediaGroupRequest) files() map[string]*InputFile {
+	files := make(map[string]*InputFile)
+	for i, item := range r.Media {
+		files[mediaAttachName(i)] = item.mediaFile()
+		files[thumbAttachName(i)] = item.thumbFile()
+	}
+
+	return files
+}
+
+// marshalMediaItem encodes the given item of a request, referencing the files it uploads by the names of the
+// multipart/form-data parts they are uploaded as.
+func marshalMediaItem(item InputMedia, i int) (json.RawMessage, error) {
+	itemJson, err := json.Marshal(item)
+	if err != nil {
+		return nil, err
+	}
+
+	var object map[string]json.RawMessage
+	err = json.Unmarshal(itemJson, &object)
+	if err != nil {
+		return nil, err
+	}
+
+	if file := item.mediaFile(); file != nil && file.needsUpload() {
+		object["media"], _ = json.Marshal("attach://" + mediaAttachName(i))
+	}
+
+	if file := item.thumbFile(); file != nil && file.needsUpload() {
+		object["thumb"], _ = json.Marshal("attach://" + thumbAttachName(i))
+	}
+
+	return json.Marshal(object)
+}
+
+func mediaAttachName(i int) string {
+	return fmt.Sprintf("media%d", i)
+}
+
+func thumbAttachName(i int) string {
+	return fmt.Sprintf("thumb%d", i)
+}
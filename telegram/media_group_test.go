@@ -0,0 +1,100 @@
+package telegram
+
+import (
+	"context"
+	"mime/multipart"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSendMediaGroup_SendFileIDsAsJson(t *testing.T) {
+	httpClient, requests := newRecordingHttpClient(`{"ok": true, "result": [{"message_id": 1}, {"message_id": 2}]}`)
+	service, _ := newMessagingService(newExecutor(httpClient, testApiUrlFmt, testToken, nil))
+
+	messages, err := service.sendMediaGroup(context.Background(), &SendMediaGroupRequest{
+		ChatID: 7,
+		Media: []InputMedia{
+			&InputMediaPhoto{Media: FileID("photo"), Caption: "album"},
+			&InputMediaVideo{Media: FileURL("https://example.com/video.mp4"), SupportsStreaming: true},
+		},
+	})
+
+	assert.NoError(t, err)
+	assert.Len(t, messages, 2)
+	assert.Equal(t, int64(2), messages[1].ID)
+	assert.JSONEq(t, `{"chat_id": 7, "media": [
+		{"type": "photo", "media": "photo", "caption": "album"},
+		{"type": "video", "media": "https://example.com/video.mp4", "supports_streaming": true}
+	]}`, requests[endpointSendMediaGroup][0])
+}
+
+func TestSendMediaGroup_AttachUploadedFiles(t *testing.T) {
+	var form *multipart.Form
+	httpClient := httpClientFunc(func(request *http.Request) (*http.Response, error) {
+		form = readMultipartForm(t, request)
+		return newResponse(http.StatusOK, `{"ok": true, "result": true}`), nil
+	})
+	service, _ := newMessagingService(newExecutor(httpClient, testApiUrlFmt, testToken, nil))
+
+	_, _ = service.sendMediaGroup(context.Background(), &SendMediaGroupRequest{
+		ChatID: 7,
+		Media: []InputMedia{
+			&InputMediaDocument{Media: FileID("document")},
+			&InputMediaDocument{
+				Media: FileFromReader("report.pdf", strings.NewReader("report")),
+				Thumb: FileFromReader("thumb.jpg", strings.NewReader("thumb")),
+			},
+		},
+	})
+
+	assert.JSONEq(t, `[
+		{"type": "document", "media": "document"},
+		{"type": "document", "media": "attach://media1", "thumb": "attach://thumb1"}
+	]`, form.Value["media"][0])
+	assert.Equal(t, "report", readFormFile(t, form.File["media1"][0]))
+	assert.Equal(t, "thumb", readFormFile(t, form.File["thumb1"][0]))
+	assert.NotContains(t, form.File, "media0")
+}
+
+func TestSendMediaGroup_ReturnErrorIfGroupSizeIsInvalid(t *testing.T) {
+	service, _ := newMessagingService(newExecutor(&mockHttpClient{}, testApiUrlFmt, testToken, nil))
+
+	for _, size := range []int{0, 1, 11} {
+		media := make([]InputMedia, size)
+		for i := range media {
+			media[i] = &InputMediaPhoto{Media: FileID("photo")}
+		}
+
+		_, err := service.sendMediaGroup(context.Background(), &SendMediaGroupRequest{ChatID: 7, Media: media})
+
+		assert.Equal(t, errInvalidMediaGroupSize, err)
+	}
+}
+
+func TestSendMediaGroup_ReturnErrorIfMediaIsMissing(t *testing.T) {
+	service, _ := newMessagingService(newExecutor(&mockHttpClient{}, testApiUrlFmt, testToken, nil))
+
+	_, err := service.sendMediaGroup(context.Background(), &SendMediaGroupRequest{
+		ChatID: 7,
+		Media:  []InputMedia{&InputMediaPhoto{Media: FileID("photo")}, &InputMediaPhoto{}},
+	})
+
+	assert.Equal(t, errMissingFile, err)
+
+	_, err = service.sendMediaGroup(context.Background(), &SendMediaGroupRequest{
+		ChatID: 7,
+		Media:  []InputMedia{&InputMediaPhoto{Media: FileID("photo")}, (*InputMediaVideo)(nil)},
+	})
+
+	assert.Equal(t, errMissingFile, err)
+}
+
+func TestMarshalWithType_EncodeNilPointerAsTypeOnly(t *testing.T) {
+	encoded, err := marshalWithType(mediaTypePhoto, (*InputMediaPhoto)(nil))
+
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"type": "photo"}`, string(encoded))
+}
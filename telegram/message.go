@@ -1,15 +1,34 @@
 package telegram // import "heytobi.dev/fuse/telegram"
 
+// Message content types, as returned by Message.ContentType.
+const (
+	ContentTypeText      = "text"
+	ContentTypeAnimation = "animation"
+	ContentTypeAudio     = "audio"
+	ContentTypeDocument  = "document"
+	ContentTypePhoto     = "photo"
+	ContentTypeSticker   = "sticker"
+	ContentTypeVideo     = "video"
+	ContentTypeVideoNote = "video_note"
+	ContentTypeVoice     = "voice"
+	ContentTypeContact   = "contact"
+	ContentTypeDice      = "dice"
+	ContentTypeGame      = "game"
+	ContentTypePoll      = "poll"
+	ContentTypeVenue     = "venue"
+	ContentTypeLocation  = "location"
+)
+
 // Message represents a message.
 // See https://core.telegram.org/bots/api#message
 type Message struct {
 	ID                            int64                          `json:"message_id"`
 	Sender                        *User                          `json:"from"`
 	SenderChat                    *Chat                          `json:"sender_chat"`
-	Date                          int                            `json:"unix_date"`
+	Date                          int                            `json:"date"`
 	Chat                          *Chat                          `json:"chat"`
 	ForwardedBy                   *User                          `json:"forward_from"`
-	OriginalChat                  *Chat                          `json:"forwarded_from_chat"`
+	OriginalChat                  *Chat                          `json:"forward_from_chat"`
 	OriginalMessageId             int64                          `json:"forward_from_message_id"`
 	ForwardSignature              string                         `json:"forward_signature"`
 	ForwardSenderName             string                         `json:"forward_sender_name"`
@@ -45,7 +64,7 @@ type Message struct {
 	NewChatPhoto                  []PhotoSize                    `json:"new_chat_photo"`
 	ChatPhotoDeleted              bool                           `json:"chat_photo_deleted"`
 	GroupChatCreated              bool                           `json:"group_chat_created"`
-	SuperGroupChatCreated         bool                           `json:"super_group_chat_created"`
+	SuperGroupChatCreated         bool                           `json:"supergroup_chat_created"`
 	ChannelChatCreated            bool                           `json:"channel_chat_created"`
 	MessageAutoDeleteTimerChanged *MessageAutoDeleteTimerChanged `json:"message_auto_delete_timer_changed"`
 	MigrateToChatID               int64                          `json:"migrate_to_chat_id"`
@@ -62,6 +81,47 @@ type Message struct {
 	ReplyMarkup                   *InlineKeyboardMarkup          `json:"reply_markup"`
 }
 
+// ContentType returns the type of content the message carries, e.g. ContentTypePhoto. It returns an empty string for
+// service messages and unsupported content.
+func (m *Message) ContentType() string {
+	switch {
+	case m.Text != "":
+		return ContentTypeText
+	case m.Animation != nil:
+		// animations are also sent as documents for backward compatibility, so they have to be checked first.
+		return ContentTypeAnimation
+	case m.Audio != nil:
+		return ContentTypeAudio
+	case m.Document != nil:
+		return ContentTypeDocument
+	case len(m.Photo) > 0:
+		return ContentTypePhoto
+	case m.Sticker != nil:
+		return ContentTypeSticker
+	case m.Video != nil:
+		return ContentTypeVideo
+	case m.VideoNote != nil:
+		return ContentTypeVideoNote
+	case m.Voice != nil:
+		return ContentTypeVoice
+	case m.Contact != nil:
+		return ContentTypeContact
+	case m.Dice != nil:
+		return ContentTypeDice
+	case m.Game != nil:
+		return ContentTypeGame
+	case m.Poll != nil:
+		return ContentTypePoll
+	case m.Venue != nil:
+		// venues also carry their location, so they have to be checked first.
+		return ContentTypeVenue
+	case m.Location != nil:
+		return ContentTypeLocation
+	}
+
+	return ""
+}
+
 // MessageEntity one special entity in a text message. For example, hashtags, usernames, URLs, etc.
 // See https://core.telegram.org/bots/api#messageentity
 type MessageEntity struct {
@@ -88,14 +148,6 @@ type SendMessageRequest struct {
 	ReplyMarkup              any             `json:"reply_markup,omitempty"`
 }
 
-type sendMessageResponse struct {
-	Ok          bool   `json:"ok"`
-	Description string `json:"description"`
-	Result      struct {
-		MessageID int    `json:"message_id"`
-		From      *User  `json:"from"`
-		Chat      *Chat  `json:"chat"`
-		Date      int    `json:"date"`
-		Text      string `json:"text"`
-	} `json:"result"`
+func (r *SendMessageRequest) targetChatID() int64 {
+	return r.ChatID
 }
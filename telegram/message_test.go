@@ -0,0 +1,25 @@
+package telegram
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMessage_DecodeDateAndForwardOrigin(t *testing.T) {
+	var message Message
+	err := json.Unmarshal([]byte(`{
+		"message_id": 1,
+		"date": 1700000000,
+		"forward_from_chat": {"id": -100, "type": "channel"},
+		"forward_from_message_id": 12,
+		"supergroup_chat_created": true
+	}`), &message)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1700000000, message.Date)
+	assert.Equal(t, int64(-100), message.OriginalChat.ID)
+	assert.Equal(t, int64(12), message.OriginalMessageId)
+	assert.True(t, message.SuperGroupChatCreated)
+}
@@ -1,87 +1,277 @@
 package telegram // import "heytobi.dev/fuse/telegram"
 
 import (
-	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
-	"io"
-	"net/http"
 
 	"github.com/pkg/errors"
-	"github.com/sirupsen/logrus"
 )
 
 type messagingService struct {
-	httpClient httpClient
-	apiUrlFmt  string
-	token      string
+	executor *executor
 }
 
-func newMessagingService(httpClient httpClient, apirUrlFmt, token string) (*messagingService, error) {
+func newMessagingService(executor *executor) (*messagingService, error) {
 	return &messagingService{
-		httpClient: httpClient,
-		apiUrlFmt:  apirUrlFmt,
-		token:      token,
+		executor: executor,
 	}, nil
 }
 
-func (s *messagingService) sendMessage(message *SendMessageRequest) (*ActionResult, error) {
-	result := &ActionResult{
-		Successful: false,
+// sendMessage sends a text message, returning the sent message.
+// See https://core.telegram.org/bots/api#sendmessage
+func (s *messagingService) sendMessage(ctx context.Context, message *SendMessageRequest) (*Message, error) {
+	if message == nil {
+		return nil, errNilMessageRequest
 	}
 
-	if message == nil {
-		result.Description = errNilMessageRequest.Error()
-		return result, errNilMessageRequest
+	return s.send(ctx, endpointSendMessage, message)
+}
+
+// sendPhoto sends a photo, returning the sent message.
+// See https://core.telegram.org/bots/api#sendphoto
+func (s *messagingService) sendPhoto(ctx context.Context, request *SendPhotoRequest) (*Message, error) {
+	if request == nil {
+		return nil, errNilMessageRequest
 	}
 
-	url := fmt.Sprintf(s.apiUrlFmt, s.token, endpointSendMessage)
+	if request.Photo == nil {
+		return nil, errMissingFile
+	}
 
-	bodyJson, err := json.Marshal(message)
-	if err != nil {
-		result.Description = "failed to marshal send message request"
-		return result, errors.Wrap(err, result.Description)
+	return s.send(ctx, endpointSendPhoto, request)
+}
+
+// sendDocument sends a general file, returning the sent message.
+// See https://core.telegram.org/bots/api#senddocument
+func (s *messagingService) sendDocument(ctx context.Context, request *SendDocumentRequest) (*Message, error) {
+	if request == nil {
+		return nil, errNilMessageRequest
+	}
+
+	if request.Document == nil {
+		return nil, errMissingFile
 	}
 
-	request, err := http.NewRequest(httpPost, url, bytes.NewBuffer(bodyJson))
+	return s.send(ctx, endpointSendDocument, request)
+}
+
+// sendAudio sends an audio file, to be displayed in the music player, returning the sent message.
+// See https://core.telegram.org/bots/api#sendaudio
+func (s *messagingService) sendAudio(ctx context.Context, request *SendAudioRequest) (*Message, error) {
+	if request == nil {
+		return nil, errNilMessageRequest
+	}
+
+	if request.Audio == nil {
+		return nil, errMissingFile
+	}
+
+	return s.send(ctx, endpointSendAudio, request)
+}
+
+// sendVideo sends a video, returning the sent message.
+// See https://core.telegram.org/bots/api#sendvideo
+func (s *messagingService) sendVideo(ctx context.Context, request *SendVideoRequest) (*Message, error) {
+	if request == nil {
+		return nil, errNilMessageRequest
+	}
+
+	if request.Video == nil {
+		return nil, errMissingFile
+	}
+
+	return s.send(ctx, endpointSendVideo, request)
+}
+
+// sendVoice sends a voice note, returning the sent message.
+// See https://core.telegram.org/bots/api#sendvoice
+func (s *messagingService) sendVoice(ctx context.Context, request *SendVoiceRequest) (*Message, error) {
+	if request == nil {
+		return nil, errNilMessageRequest
+	}
+
+	if request.Voice == nil {
+		return nil, errMissingFile
+	}
+
+	return s.send(ctx, endpointSendVoice, request)
+}
+
+// sendAnimation sends an animation, i.e. a GIF or a video without sound, returning the sent message.
+// See https://core.telegram.org/bots/api#sendanimation
+func (s *messagingService) sendAnimation(ctx context.Context, request *SendAnimationRequest) (*Message, error) {
+	if request == nil {
+		return nil, errNilMessageRequest
+	}
+
+	if request.Animation == nil {
+		return nil, errMissingFile
+	}
+
+	return s.send(ctx, endpointSendAnimation, request)
+}
+
+// sendMediaGroup sends an album, returning the sent messages.
+// See https://core.telegram.org/bots/api#sendmediagroup
+func (s *messagingService) sendMediaGroup(ctx context.Context, request *SendMediaGroupRequest) ([]*Message, error) {
+	if request == nil {
+		return nil, errNilMessageRequest
+	}
+
+	if len(request.Media) < minMediaGroupSize || len(request.Media) > maxMediaGroupSize {
+		return nil, errInvalidMediaGroupSize
+	}
+
+	for _, media := range request.Media {
+		if isNil(media) || media.mediaFile() == nil {
+			return nil, errMissingFile
+		}
+	}
+
+	var sent []*Message
+	err := s.executor.execute(ctx, endpointSendMediaGroup, request, &sent)
 	if err != nil {
-		result.Description = "failed to create send message request"
-		return result, errors.Wrap(err, result.Description)
+		return nil, err
 	}
-	request.Header.Set("Content-Type", "application/json")
 
-	response, err := s.httpClient.Do(request)
+	return sent, nil
+}
+
+// editMessageText edits the text of a message, returning the edited message unless it was sent through an inline
+// query.
+// See https://core.telegram.org/bots/api#editmessagetext
+func (s *messagingService) editMessageText(ctx context.Context, request *EditMessageTextRequest) (*Message, error) {
+	if request == nil {
+		return nil, errNilMessageRequest
+	}
+
+	if !request.address().isValid() {
+		return nil, errInvalidMessageAddress
+	}
+
+	return s.edit(ctx, endpointEditMessageText, request)
+}
+
+// editMessageCaption edits the caption of a message, returning the edited message unless it was sent through an
+// inline query.
+// See https://core.telegram.org/bots/api#editmessagecaption
+func (s *messagingService) editMessageCaption(
+	ctx context.Context,
+	request *EditMessageCaptionRequest,
+) (*Message, error) {
+	if request == nil {
+		return nil, errNilMessageRequest
+	}
+
+	if !request.address().isValid() {
+		return nil, errInvalidMessageAddress
+	}
+
+	return s.edit(ctx, endpointEditMessageCaption, request)
+}
+
+// editMessageMedia replaces the media of a message, returning the edited message unless it was sent through an
+// inline query.
+// See https://core.telegram.org/bots/api#editmessagemedia
+func (s *messagingService) editMessageMedia(ctx context.Context, request *EditMessageMediaRequest) (*Message, error) {
+	if request == nil {
+		return nil, errNilMessageRequest
+	}
+
+	if !request.address().isValid() {
+		return nil, errInvalidMessageAddress
+	}
+
+	if isNil(request.Media) || request.Media.mediaFile() == nil {
+		return nil, errMissingFile
+	}
+
+	return s.edit(ctx, endpointEditMessageMedia, request)
+}
+
+// editMessageReplyMarkup edits the inline keyboard of a message, returning the edited message unless it was sent
+// through an inline query.
+// See https://core.telegram.org/bots/api#editmessagereplymarkup
+func (s *messagingService) editMessageReplyMarkup(
+	ctx context.Context,
+	request *EditMessageReplyMarkupRequest,
+) (*Message, error) {
+	if request == nil {
+		return nil, errNilMessageRequest
+	}
+
+	if !request.address().isValid() {
+		return nil, errInvalidMessageAddress
+	}
+
+	return s.edit(ctx, endpointEditMessageReplyMarkup, request)
+}
+
+// deleteMessage deletes a message.
+// See https://core.telegram.org/bots/api#deletemessage
+func (s *messagingService) deleteMessage(ctx context.Context, request *DeleteMessageRequest) (bool, error) {
+	if request == nil {
+		return false, errNilMessageRequest
+	}
+
+	var deleted bool
+	err := s.executor.execute(ctx, endpointDeleteMessage, request, &deleted)
 	if err != nil {
-		result.Description = "http request failed"
-		return result, errors.Wrap(err, result.Description)
+		return false, err
+	}
+
+	return deleted, nil
+}
+
+// deleteMessages deletes several messages of the same chat.
+// See https://core.telegram.org/bots/api#deletemessages
+func (s *messagingService) deleteMessages(ctx context.Context, request *DeleteMessagesRequest) (bool, error) {
+	if request == nil {
+		return false, errNilMessageRequest
 	}
-	defer func(body io.ReadCloser) {
-		err := body.Close()
-		if err != nil {
-			logrus.WithError(err).Error("failed to close response body")
-		}
-	}(response.Body)
 
-	if response.StatusCode != http.StatusOK {
-		result.Description = fmt.Sprintf("unexpected response code: %d, %s", response.StatusCode, response.Body)
-		return result, errors.New(result.Description)
+	if len(request.MessageIDs) == 0 || len(request.MessageIDs) > maxDeletedMessages {
+		return false, errInvalidDeleteCount
 	}
 
-	responseBody, err := io.ReadAll(response.Body)
+	var deleted bool
+	err := s.executor.execute(ctx, endpointDeleteMessages, request, &deleted)
 	if err != nil {
-		result.Description = "failed to parse send message response body"
-		return result, errors.Wrap(err, result.Description)
+		return false, err
 	}
 
-	var resp sendMessageResponse
-	err = json.Unmarshal(responseBody, &resp)
+	return deleted, nil
+}
+
+// edit calls an edit endpoint, which returns the edited message, or true if the message was sent through an inline
+// query.
+func (s *messagingService) edit(ctx context.Context, endpoint string, request any) (*Message, error) {
+	var result json.RawMessage
+	err := s.executor.execute(ctx, endpoint, request, &result)
 	if err != nil {
-		result.Description = "failed to unmarshall sendMessage response"
-		return result, errors.Wrap(err, result.Description)
+		return nil, err
+	}
+
+	if string(result) == "true" {
+		return nil, nil
 	}
 
-	result.Successful = resp.Ok
-	result.Description = resp.Description
+	var edited Message
+	err = json.Unmarshal(result, &edited)
+	if err != nil {
+		return nil, errors.Wrap(err, fmt.Sprintf("failed to unmarshal %s result", endpoint))
+	}
+
+	return &edited, nil
+}
+
+func (s *messagingService) send(ctx context.Context, endpoint string, request any) (*Message, error) {
+	var sent Message
+	err := s.executor.execute(ctx, endpoint, request, &sent)
+	if err != nil {
+		return nil, err
+	}
 
-	return result, nil
+	return &sent, nil
 }
@@ -2,6 +2,7 @@ package telegram
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"io"
@@ -20,63 +21,66 @@ const (
 
 func TestSendMessage_ReturnsErrorIfMessageIsNil(t *testing.T) {
 	httpClient := &mockHttpClient{}
-	service, _ := newMessagingService(httpClient, testApiUrlFmt, testToken)
+	service, _ := newMessagingService(newExecutor(httpClient, testApiUrlFmt, testToken, nil))
 
-	result, err := service.sendMessage(nil)
+	result, err := service.sendMessage(context.Background(), nil)
 
-	assert.False(t, result.Successful)
+	assert.Nil(t, result)
 	assert.Error(t, err)
 	assert.Equal(t, errNilMessageRequest, err)
 }
 
-func TestSendMessage_ActionResultIsSuccessfulIfMessageIsSentSuccessfully(t *testing.T) {
-	httpClient := &mockHttpClient{}
-	response := sendMessageResponse{Ok: true}
-	responseJson, _ := json.Marshal(response)
-	responseBody := io.NopCloser(bytes.NewBufferString(string(responseJson)))
-	httpClient.On("Do", mock.Anything, mock.Anything).Return(&http.Response{
-		StatusCode: http.StatusOK,
-		Body:       responseBody,
-	}, nil)
-	service, _ := newMessagingService(httpClient, testApiUrlFmt, testToken)
+func TestSendMessage_ReturnSentMessage(t *testing.T) {
+	httpClient, _ := newRecordingHttpClient(
+		`{"ok": true, "result": {"message_id": 42, "date": 1700000000, "chat": {"id": 7, "type": "private"}, ` +
+			`"text": "test", "entities": [{"type": "bold", "offset": 0, "length": 4}]}}`,
+	)
+	service, _ := newMessagingService(newExecutor(httpClient, testApiUrlFmt, testToken, nil))
 
-	message := &SendMessageRequest{}
-	result, err := service.sendMessage(message)
+	message := &SendMessageRequest{ChatID: 7, Text: "test"}
+	result, err := service.sendMessage(context.Background(), message)
 
-	assert.True(t, result.Successful)
 	assert.Nil(t, err)
+	assert.Equal(t, int64(42), result.ID)
+	assert.Equal(t, 1700000000, result.Date)
+	assert.Equal(t, int64(7), result.Chat.ID)
+	assert.Equal(t, "test", result.Text)
+	assert.Equal(t, []MessageEntity{{Type: "bold", Length: 4}}, result.Entities)
 }
 
 func TestSendMessage_ReturnsErrorIfResponseCodeIsNot200(t *testing.T) {
 	httpClient := &mockHttpClient{}
-	response := sendMessageResponse{Ok: false}
+	response := apiResponse{Ok: false, ErrorCode: 500, Description: "Internal Server Error"}
 	responseJson, _ := json.Marshal(response)
 	responseBody := io.NopCloser(bytes.NewBufferString(string(responseJson)))
 	httpClient.On("Do", mock.Anything, mock.Anything).Return(&http.Response{
 		StatusCode: http.StatusInternalServerError,
 		Body:       responseBody,
 	}, nil)
-	service, _ := newMessagingService(httpClient, testApiUrlFmt, testToken)
+	service, _ := newMessagingService(newExecutor(httpClient, testApiUrlFmt, testToken, nil))
 
 	message := &SendMessageRequest{}
-	result, err := service.sendMessage(message)
+	result, err := service.sendMessage(context.Background(), message)
 
-	assert.False(t, result.Successful)
+	assert.Nil(t, result)
 	assert.Error(t, err)
-	assert.True(t, strings.HasPrefix(result.Description, "unexpected response code: 500"))
+	var apiErr *APIError
+	assert.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, 500, apiErr.ErrorCode)
+	assert.Equal(t, "Internal Server Error", apiErr.Description)
 }
 
 func TestSendMessage_ReturnsErrorIfRequestFails(t *testing.T) {
 	httpClient := &mockHttpClient{}
 	httpClient.On("Do", mock.Anything, mock.Anything).Return(nil, errors.New("error"))
-	service, _ := newMessagingService(httpClient, testApiUrlFmt, testToken)
+	service, _ := newMessagingService(newExecutor(httpClient, testApiUrlFmt, testToken, nil))
 
 	message := &SendMessageRequest{}
-	result, err := service.sendMessage(message)
+	result, err := service.sendMessage(context.Background(), message)
 
-	assert.False(t, result.Successful)
+	assert.Nil(t, result)
 	assert.Error(t, err)
-	assert.True(t, strings.EqualFold(err.Error(), "http request failed: error"))
+	assert.True(t, strings.EqualFold(err.Error(), "sendMessage request failed: error"))
 }
 
 func TestSendMessage_ReturnsErrorIfUnableToParseResponse(t *testing.T) {
@@ -86,12 +90,27 @@ func TestSendMessage_ReturnsErrorIfUnableToParseResponse(t *testing.T) {
 		StatusCode: http.StatusOK,
 		Body:       responseBody,
 	}, nil)
-	service, _ := newMessagingService(httpClient, testApiUrlFmt, testToken)
+	service, _ := newMessagingService(newExecutor(httpClient, testApiUrlFmt, testToken, nil))
 
 	message := &SendMessageRequest{}
-	result, err := service.sendMessage(message)
+	result, err := service.sendMessage(context.Background(), message)
 
-	assert.False(t, result.Successful)
+	assert.Nil(t, result)
 	assert.Error(t, err)
-	assert.True(t, strings.EqualFold(result.Description, "failed to unmarshall sendMessage response"))
+	assert.True(t, strings.HasPrefix(err.Error(), "failed to unmarshal sendMessage response"))
+}
+
+func TestSendMessage_PassContextToRequest(t *testing.T) {
+	type key struct{}
+	var requestCtx context.Context
+	httpClient := httpClientFunc(func(request *http.Request) (*http.Response, error) {
+		requestCtx = request.Context()
+		return nil, errors.New("error")
+	})
+	service, _ := newMessagingService(newExecutor(httpClient, testApiUrlFmt, testToken, nil))
+
+	ctx := context.WithValue(context.Background(), key{}, "value")
+	_, _ = service.sendMessage(ctx, &SendMessageRequest{})
+
+	assert.Equal(t, "value", requestCtx.Value(key{}))
 }
@@ -0,0 +1,83 @@
+package telegram // import "heytobi.dev/fuse/telegram"
+
+import (
+	"context"
+)
+
+// Middleware wraps a HandlerFunc with cross-cutting behaviour, such as logging, authorization or panic recovery.
+// A middleware can short-circuit the processing of an update by returning without calling the next handler.
+type Middleware func(next HandlerFunc) HandlerFunc
+
+// Group is a set of handlers sharing middleware. Handlers registered through a group are wrapped by the group's
+// middleware, in addition to the middleware registered on the bot.
+type Group struct {
+	bot        *Bot
+	parent     *Group
+	middleware []Middleware
+}
+
+// Use registers middleware that wraps every handler registered on the bot, whether it handles a command, is the
+// default handler or handles another type of update. Middleware also see updates that no handler matches, only
+// commands addressed to other bots are ignored before reaching them. Middleware are invoked in the order they are
+// registered, the first one being the outermost. Use isn't safe to call concurrently with the processing of updates,
+// middleware should be registered before the bot is started.
+func (b *Bot) Use(middleware ...Middleware) {
+	b.middleware = append(b.middleware, middleware...)
+}
+
+// Group creates a group of handlers wrapped by the given middleware.
+func (b *Bot) Group(middleware ...Middleware) *Group {
+	return &Group{
+		bot:        b,
+		middleware: middleware,
+	}
+}
+
+// Use registers middleware that wraps every handler registered through the group.
+func (g *Group) Use(middleware ...Middleware) {
+	g.middleware = append(g.middleware, middleware...)
+}
+
+// Group creates a nested group, its handlers are wrapped by the middleware of this group and the given middleware.
+func (g *Group) Group(middleware ...Middleware) *Group {
+	return &Group{
+		bot:        g.bot,
+		parent:     g,
+		middleware: middleware,
+	}
+}
+
+// RegisterHandler registers the given handler function to handle invocations of the given command.
+// See Bot.RegisterHandler.
+func (g *Group) RegisterHandler(command string, handler HandlerFunc) error {
+	return g.bot.RegisterHandler(command, g.wrap(handler))
+}
+
+// RegisterDefaultHandler registers the given handler function as the bot's default handler.
+// See Bot.RegisterDefaultHandler.
+func (g *Group) RegisterDefaultHandler(handler HandlerFunc) error {
+	return g.bot.RegisterDefaultHandler(g.wrap(handler))
+}
+
+// wrap returns a handler invoking the given handler through the middleware of the group and its parents. Middleware are
+// resolved when the handler is invoked, so middleware registered after the handler still apply.
+func (g *Group) wrap(handler HandlerFunc) HandlerFunc {
+	wrapped := func(ctx context.Context, update *Update) error {
+		return chain(handler, g.middleware)(ctx, update)
+	}
+
+	if g.parent != nil {
+		return g.parent.wrap(wrapped)
+	}
+
+	return wrapped
+}
+
+// chain wraps the given handler with the given middleware, the first middleware being the outermost.
+func chain(handler HandlerFunc, middleware []Middleware) HandlerFunc {
+	for i := len(middleware) - 1; i >= 0; i-- {
+		handler = middleware[i](handler)
+	}
+
+	return handler
+}
@@ -0,0 +1,127 @@
+package telegram
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func recordingMiddleware(name string, calls *[]string) Middleware {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(ctx context.Context, update *Update) error {
+			*calls = append(*calls, name)
+			return next(ctx, update)
+		}
+	}
+}
+
+func TestUse_WrapHandlersInRegistrationOrder(t *testing.T) {
+	var calls []string
+
+	bot, _ := NewBot(&Config{Token: "test"}, &mockHttpClient{})
+	bot.Use(recordingMiddleware("first", &calls), recordingMiddleware("second", &calls))
+	_ = bot.RegisterHandler("/start", func(ctx context.Context, update *Update) error {
+		calls = append(calls, "handler")
+		return nil
+	})
+
+	err := bot.ProcessUpdate(context.Background(), &Update{Message: &Message{Text: "/start"}})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"first", "second", "handler"}, calls)
+}
+
+func TestUse_WrapDefaultHandler(t *testing.T) {
+	var calls []string
+
+	bot, _ := NewBot(&Config{Token: "test"}, &mockHttpClient{})
+	_ = bot.RegisterDefaultHandler(func(ctx context.Context, update *Update) error {
+		calls = append(calls, "default")
+		return nil
+	})
+	bot.Use(recordingMiddleware("middleware", &calls))
+
+	err := bot.ProcessUpdate(context.Background(), &Update{Message: &Message{Text: "hello"}})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"middleware", "default"}, calls)
+}
+
+func TestUse_WrapUpdatesWithoutHandler(t *testing.T) {
+	var calls []string
+
+	bot, _ := NewBot(&Config{Token: "test"}, &mockHttpClient{})
+	bot.Use(recordingMiddleware("middleware", &calls))
+
+	err := bot.ProcessUpdate(context.Background(), &Update{Message: &Message{Text: "hello"}})
+	assert.NoError(t, err)
+	err = bot.ProcessUpdate(context.Background(), &Update{EditedMessage: &Message{Text: "hello"}})
+	assert.NoError(t, err)
+
+	assert.Equal(t, []string{"middleware", "middleware"}, calls)
+}
+
+func TestUse_MiddlewareCanShortCircuit(t *testing.T) {
+	errUnauthorized := errors.New("unauthorized")
+	handled := false
+
+	bot, _ := NewBot(&Config{Token: "test"}, &mockHttpClient{})
+	bot.Use(func(next HandlerFunc) HandlerFunc {
+		return func(ctx context.Context, update *Update) error {
+			return errUnauthorized
+		}
+	})
+	_ = bot.RegisterDefaultHandler(func(ctx context.Context, update *Update) error {
+		handled = true
+		return nil
+	})
+
+	err := bot.ProcessUpdate(context.Background(), &Update{Message: &Message{Text: "hello"}})
+
+	assert.Equal(t, errUnauthorized, err)
+	assert.False(t, handled)
+}
+
+func TestGroup_WrapOnlyHandlersRegisteredThroughTheGroup(t *testing.T) {
+	var calls []string
+
+	bot, _ := NewBot(&Config{Token: "test"}, &mockHttpClient{})
+	bot.Use(recordingMiddleware("bot", &calls))
+
+	admin := bot.Group(recordingMiddleware("admin", &calls))
+	superAdmin := admin.Group(recordingMiddleware("superadmin", &calls))
+
+	_ = superAdmin.RegisterHandler("/ban", func(ctx context.Context, update *Update) error {
+		calls = append(calls, "ban")
+		return nil
+	})
+	_ = bot.RegisterHandler("/start", func(ctx context.Context, update *Update) error {
+		calls = append(calls, "start")
+		return nil
+	})
+
+	_ = bot.ProcessUpdate(context.Background(), &Update{Message: &Message{Text: "/ban"}})
+	assert.Equal(t, []string{"bot", "admin", "superadmin", "ban"}, calls)
+
+	calls = nil
+	_ = bot.ProcessUpdate(context.Background(), &Update{Message: &Message{Text: "/start"}})
+	assert.Equal(t, []string{"bot", "start"}, calls)
+}
+
+func TestGroup_ApplyMiddlewareRegisteredAfterHandlers(t *testing.T) {
+	var calls []string
+
+	bot, _ := NewBot(&Config{Token: "test"}, &mockHttpClient{})
+	group := bot.Group()
+	_ = group.RegisterDefaultHandler(func(ctx context.Context, update *Update) error {
+		calls = append(calls, "default")
+		return nil
+	})
+	group.Use(recordingMiddleware("group", &calls))
+
+	_ = bot.ProcessUpdate(context.Background(), &Update{Message: &Message{Text: "hello"}})
+
+	assert.Equal(t, []string{"group", "default"}, calls)
+}
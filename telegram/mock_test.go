@@ -1,8 +1,13 @@
 package telegram
 
 import (
-	"github.com/stretchr/testify/mock"
+	"bytes"
+	"io"
 	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/stretchr/testify/mock"
 )
 
 type mockHttpClient struct {
@@ -18,3 +23,42 @@ func (m *mockHttpClient) Do(request *http.Request) (*http.Response, error) {
 
 	return args.Get(0).(*http.Response), args.Error(1)
 }
+
+type httpClientFunc func(request *http.Request) (*http.Response, error)
+
+func (f httpClientFunc) Do(request *http.Request) (*http.Response, error) {
+	return f(request)
+}
+
+// newRecordingHttpClient returns an http client that records the body of every request by endpoint and responds
+// with the given body.
+func newRecordingHttpClient(responseBody string) (httpClient, map[string][]string) {
+	var mu sync.Mutex
+	requests := make(map[string][]string)
+
+	return httpClientFunc(func(request *http.Request) (*http.Response, error) {
+		body, _ := io.ReadAll(request.Body)
+		endpoint := request.URL.Path[strings.LastIndex(request.URL.Path, "/")+1:]
+
+		mu.Lock()
+		requests[endpoint] = append(requests[endpoint], string(body))
+		mu.Unlock()
+
+		return newResponse(http.StatusOK, responseBody), nil
+	}), requests
+}
+
+// newResponse returns a response with the given status code and body.
+func newResponse(statusCode int, body string) *http.Response {
+	return &http.Response{
+		StatusCode: statusCode,
+		Body:       io.NopCloser(bytes.NewBufferString(body)),
+	}
+}
+
+// requestTo returns an argument matcher for requests made to the given endpoint.
+func requestTo(endpoint string) any {
+	return mock.MatchedBy(func(request *http.Request) bool {
+		return strings.HasSuffix(request.URL.Path, "/"+endpoint)
+	})
+}
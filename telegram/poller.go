@@ -1,12 +1,10 @@
 package telegram // import "heytobi.dev/fuse/telegram"
 
 import (
-	"bytes"
-	"encoding/json"
-	"fmt"
-	"io"
-	"net/http"
+	"context"
 	"sort"
+	"sync"
+	"time"
 
 	"heytobi.dev/fuse/job"
 
@@ -14,16 +12,53 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
+const (
+	PollingModeLong     = "long"
+	PollingModeInterval = "interval"
+
+	OffsetCommitOnHandoff     = "handoff"
+	OffsetCommitOnAcknowledge = "acknowledge"
+
+	defaultLongPollingTimeout = 30
+
+	// pollingRequestTimeoutMargin is added to the polling timeout to bound getUpdates requests, giving Telegram time
+	// to respond once the long poll expires.
+	pollingRequestTimeoutMargin = 10 * time.Second
+	pollingErrorBackoff         = time.Second
+)
+
 // Poller is responsible for continuously checking for updates from Telegram using the getUpdates method.
 // See https://core.telegram.org/bots/api#getupdates
 type Poller struct {
 	config      *Config
-	httpClient  httpClient
+	executor    *executor
 	updatesChan chan *Update
 	offset      int
-	apiUrlFmt   string
+	confirmed   int
+	cancel      context.CancelFunc
+	done        chan struct{}
+	mu          sync.Mutex
+	pending     map[int]bool
+	ackSignal   chan struct{}
 }
 
+// NewPoller initializes a Poller.
+//
+// If no PollingMode is specified in the config, it defaults to long polling, in which case the next getUpdates call is
+// made as soon as the previous one returns. If no PollingTimeout is specified for long polling, it defaults to 30
+// seconds. With the interval polling mode, getUpdates is called every PollingIntervalMS milliseconds instead.
+//
+// Updates are handed off in update_id order. By default, an update is confirmed to Telegram as soon as it has been
+// handed off, with the OffsetCommitOnAcknowledge mode, it is only confirmed once its handler has completed, so updates
+// that were not processed before the poller was stopped are received again. Offsets committed since the last
+// getUpdates call are confirmed when the poller stops, if Telegram can't be reached then, the updates they cover are
+// received again the next time the poller is started.
+//
+// It returns an error if any of these conditions are met:
+//   - The given config is nil
+//   - The config has no token
+//   - The configured PollingMode is invalid
+//   - The configured OffsetCommitMode is invalid.
 func NewPoller(config *Config, httpClient httpClient) (*Poller, error) {
 	if config == nil {
 		return nil, errNilConfig
@@ -33,93 +68,288 @@ func NewPoller(config *Config, httpClient httpClient) (*Poller, error) {
 		return nil, errMissingToken
 	}
 
+	if config.PollingMode == "" {
+		config.PollingMode = PollingModeLong
+	}
+
+	if config.PollingMode != PollingModeLong && config.PollingMode != PollingModeInterval {
+		return nil, errInvalidPollingMode
+	}
+
+	if config.PollingMode == PollingModeLong && config.PollingTimeout == 0 {
+		config.PollingTimeout = defaultLongPollingTimeout
+	}
+
+	if config.OffsetCommitMode == "" {
+		config.OffsetCommitMode = OffsetCommitOnHandoff
+	}
+
+	if config.OffsetCommitMode != OffsetCommitOnHandoff && config.OffsetCommitMode != OffsetCommitOnAcknowledge {
+		return nil, errInvalidOffsetCommitMode
+	}
+
 	return &Poller{
-		httpClient:  httpClient,
+		executor:    newExecutor(httpClient, deriveBotApiUrlBase(config)+"/bot%s/%s", config.Token, config.RetryPolicy),
 		config:      config,
 		updatesChan: make(chan *Update),
-		apiUrlFmt:   deriveBotApiUrlBase(config) + "/bot%s/%s",
+		pending:     make(map[int]bool),
+		ackSignal:   make(chan struct{}, 1),
 	}, nil
 }
 
-func (p *Poller) start() error {
-	scheduledJob, err := job.NewScheduledJob(p, p.config.PollingIntervalMS)
-	if err != nil {
-		return errors.Wrap(err, "failed to initialize scheduled job for poller")
+func (p *Poller) start(ctx context.Context) error {
+	poll := p.longPoll
+	if p.config.PollingMode == PollingModeInterval {
+		scheduledJob, err := job.NewScheduledJob(p, p.config.PollingIntervalMS)
+		if err != nil {
+			return errors.Wrap(err, "failed to initialize scheduled job for poller")
+		}
+
+		poll = func(ctx context.Context) {
+			scheduledJob.StartWithContext(ctx)
+			<-ctx.Done()
+			scheduledJob.Stop()
+		}
 	}
 
-	scheduledJob.Start()
+	ctx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+
+	p.mu.Lock()
+	p.updatesChan = make(chan *Update)
+	p.pending = make(map[int]bool)
+	p.cancel = cancel
+	p.done = done
+	p.mu.Unlock()
+
+	go func() {
+		defer close(done)
+		poll(ctx)
+	}()
 
 	return nil
 }
 
-func (p *Poller) getUpdatesChannel() <-chan *Update {
-	return p.updatesChan
+// stop stops polling for updates, confirms the committed offset and closes the updates channel. Updates that were
+// received but not handed off yet are not confirmed and will be received again the next time the poller is started.
+func (p *Poller) stop() {
+	p.mu.Lock()
+	cancel, done, updatesChan := p.cancel, p.done, p.updatesChan
+	p.cancel = nil
+	p.mu.Unlock()
+
+	if cancel == nil {
+		return
+	}
+
+	cancel()
+	<-done
+	p.confirmOffset()
+	close(updatesChan)
 }
 
-func (p *Poller) getUpdates() ([]*Update, error) {
-	url := fmt.Sprintf(p.apiUrlFmt, p.config.Token, endpointGetUpdates)
+// confirmOffset confirms the offset committed since the last getUpdates call to Telegram, which otherwise only
+// happens on the next getUpdates call. The update it might return is not handed off, so it is received again.
+func (p *Poller) confirmOffset() {
+	p.commitAcknowledged()
+	if p.offset <= p.confirmed {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), pollingRequestTimeoutMargin)
+	defer cancel()
 
 	requestBody := getUpdatesRequest{
 		Offset:         p.offset,
-		Limit:          p.config.PollingUpdatesLimit,
-		Timeout:        p.config.PollingTimeout,
+		Limit:          1,
 		AllowedUpdates: p.config.AllowedUpdates,
 	}
 
-	bodyJson, err := json.Marshal(requestBody)
+	err := p.executor.execute(ctx, endpointGetUpdates, requestBody, nil)
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to construct getUpdates request body")
+		logrus.WithError(err).Error("failed to confirm updates offset")
+		return
 	}
 
-	request, err := http.NewRequest(httpPost, url, bytes.NewBuffer(bodyJson))
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to create getUpdates request")
+	p.confirmed = p.offset
+}
+
+// longPoll calls getUpdates in a loop until the given context is cancelled, each call starting as soon as the previous
+// one returns.
+func (p *Poller) longPoll(ctx context.Context) {
+	for ctx.Err() == nil {
+		err := p.poll(ctx)
+		if err == nil || ctx.Err() != nil {
+			continue
+		}
+
+		logrus.WithError(err).Error("failed to get updates")
+
+		select {
+		case <-ctx.Done():
+		case <-time.After(pollingBackoff(err)):
+		}
 	}
-	request.Header.Set("Content-Type", "application/json")
+}
 
-	response, err := p.httpClient.Do(request)
-	if err != nil {
-		return nil, errors.Wrap(err, "getUpdates call failed")
+// pollingBackoff returns how long to wait before polling again after the given error, Telegram might require waiting
+// longer through retry_after.
+func pollingBackoff(err error) time.Duration {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.ResponseParameters != nil && apiErr.ResponseParameters.RetryAfter > 0 {
+		return time.Duration(apiErr.ResponseParameters.RetryAfter) * time.Second
 	}
-	defer func(body io.ReadCloser) {
-		err := body.Close()
-		if err != nil {
-			logrus.WithError(err).Error("failed to close response body")
-		}
-	}(response.Body)
 
-	responseBody, err := io.ReadAll(response.Body)
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to parse getUpdates response body")
+	return pollingErrorBackoff
+}
+
+func (p *Poller) getUpdatesChannel() <-chan *Update {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.updatesChan
+}
+
+func (p *Poller) getUpdates(ctx context.Context) ([]*Update, error) {
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(p.config.PollingTimeout)*time.Second+pollingRequestTimeoutMargin)
+	defer cancel()
+
+	requestBody := getUpdatesRequest{
+		Offset:         p.offset,
+		Limit:          p.config.PollingUpdatesLimit,
+		Timeout:        p.config.PollingTimeout,
+		AllowedUpdates: p.config.AllowedUpdates,
 	}
 
-	var updates getUpdatesResponse
-	err = json.Unmarshal(responseBody, &updates)
+	var updates []*Update
+	err := p.executor.execute(ctx, endpointGetUpdates, requestBody, &updates)
 	if err != nil {
-		return nil, errors.Wrap(err, fmt.Sprintf("failed to unmarshal getUpdates response: %s", string(responseBody[:])))
+		return nil, err
 	}
+	p.confirmed = requestBody.Offset
 
-	return updates.Result, nil
+	return updates, nil
 }
 
-// Run ...
-func (p *Poller) Run() {
-	updates, err := p.getUpdates()
-	if err != nil {
+// Run fetches pending updates and hands them off on the updates channel.
+func (p *Poller) Run(ctx context.Context) {
+	err := p.poll(ctx)
+	if err != nil && ctx.Err() == nil {
 		logrus.WithError(err).Error("failed to get updates")
 	}
+}
+
+func (p *Poller) poll(ctx context.Context) error {
+	updates, err := p.getUpdates(ctx)
+	if err != nil {
+		return err
+	}
 
 	sort.Slice(updates, func(i, j int) bool {
 		return updates[i].ID < updates[j].ID
 	})
 
+	p.mu.Lock()
+	updatesChan := p.updatesChan
+	p.mu.Unlock()
+
+	commitOnAcknowledge := p.config.OffsetCommitMode == OffsetCommitOnAcknowledge
+
 	for _, update := range updates {
-		update := update
-		go func(u *Update) {
-			p.updatesChan <- update
-			if update.ID >= p.offset {
-				p.offset = update.ID + 1
-			}
-		}(update)
+		// batches can overlap if a previous batch was not fully confirmed, skip what was already handed off.
+		if update.ID < p.offset {
+			continue
+		}
+
+		if commitOnAcknowledge {
+			p.track(update.ID)
+		}
+
+		select {
+		case updatesChan <- update:
+		case <-ctx.Done():
+			p.untrack(update.ID)
+			return ctx.Err()
+		}
+
+		if !commitOnAcknowledge {
+			p.offset = update.ID + 1
+		}
+	}
+
+	if commitOnAcknowledge {
+		return p.awaitAcknowledgements(ctx)
+	}
+
+	return nil
+}
+
+// acknowledge marks the given update as processed. It has no effect unless the poller is configured to commit offsets
+// on acknowledgement.
+func (p *Poller) acknowledge(update *Update) {
+	p.mu.Lock()
+	if _, isPending := p.pending[update.ID]; isPending {
+		p.pending[update.ID] = true
+	}
+	p.mu.Unlock()
+
+	select {
+	case p.ackSignal <- struct{}{}:
+	default:
 	}
 }
+
+// awaitAcknowledgements blocks until every update handed off has been acknowledged, advancing the offset past
+// acknowledged updates as they come in. Offsets are only advanced over contiguous acknowledged updates, so that an
+// update still being processed is never confirmed.
+func (p *Poller) awaitAcknowledgements(ctx context.Context) error {
+	for {
+		if p.commitAcknowledged() == 0 {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-p.ackSignal:
+		}
+	}
+}
+
+// commitAcknowledged advances the offset over the lowest acknowledged updates and returns how many updates are still
+// pending.
+func (p *Poller) commitAcknowledged() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	ids := make([]int, 0, len(p.pending))
+	for id := range p.pending {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+
+	for _, id := range ids {
+		if !p.pending[id] {
+			break
+		}
+
+		p.offset = id + 1
+		delete(p.pending, id)
+	}
+
+	return len(p.pending)
+}
+
+func (p *Poller) track(updateID int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.pending[updateID] = false
+}
+
+func (p *Poller) untrack(updateID int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	delete(p.pending, updateID)
+}
@@ -1,12 +1,14 @@
 package telegram
 
 import (
-	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
-	"io"
 	"net/http"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -28,39 +30,312 @@ func TestNewPoller_ReturnErrorIfTokenIsMissing(t *testing.T) {
 	assert.Equal(t, errMissingToken, err)
 }
 
+func TestNewPoller_DefaultToLongPolling(t *testing.T) {
+	config := &Config{Token: "test"}
+	_, err := NewPoller(config, nil)
+
+	assert.NoError(t, err)
+	assert.Equal(t, PollingModeLong, config.PollingMode)
+	assert.Equal(t, defaultLongPollingTimeout, config.PollingTimeout)
+}
+
+func TestNewPoller_ReturnErrorIfPollingModeIsInvalid(t *testing.T) {
+	poller, err := NewPoller(&Config{Token: "test", PollingMode: "invalid"}, nil)
+
+	assert.Nil(t, poller)
+	assert.Equal(t, errInvalidPollingMode, err)
+}
+
+func TestGetUpdates_BoundRequestByPollingTimeout(t *testing.T) {
+	var deadline time.Time
+	httpClient := httpClientFunc(func(request *http.Request) (*http.Response, error) {
+		deadline, _ = request.Context().Deadline()
+		return nil, errors.New("fails")
+	})
+
+	poller, _ := NewPoller(&Config{Token: "test", PollingTimeout: 5}, httpClient)
+	_, _ = poller.getUpdates(context.Background())
+
+	assert.WithinDuration(t, time.Now().Add(5*time.Second+pollingRequestTimeoutMargin), deadline, time.Second)
+}
+
 func TestGetUpdates_ReturnErrorIfRequestFails(t *testing.T) {
 	httpClient := &mockHttpClient{}
 	httpClient.On("Do", mock.Anything, mock.Anything).Return(nil, errors.New("fails"))
 
 	poller, _ := NewPoller(&Config{Token: "test"}, httpClient)
-	updates, err := poller.getUpdates()
+	updates, err := poller.getUpdates(context.Background())
 
 	assert.Nil(t, updates)
 	assert.Error(t, err)
 }
 
 func TestGetUpdates_GetUpdatesSuccessfully(t *testing.T) {
-	response := getUpdatesResponse{
-		Ok: false,
-		Result: []*Update{
-			{
-				ID: 1,
-			},
-			{
-				ID: 1,
-			},
-		},
-	}
-	responseJson, _ := json.Marshal(response)
-	body := io.NopCloser(bytes.NewBuffer(responseJson))
-
 	httpClient := &mockHttpClient{}
-	httpClient.On("Do", mock.Anything, mock.Anything).Return(&http.Response{Body: body}, nil)
+	httpClient.On("Do", mock.Anything, mock.Anything).Return(newGetUpdatesResponse(1, 1), nil)
 
 	poller, _ := NewPoller(&Config{Token: "test"}, httpClient)
-	updates, err := poller.getUpdates()
+	updates, err := poller.getUpdates(context.Background())
 
 	assert.NotNil(t, updates)
 	assert.Equal(t, 1, updates[0].ID)
 	assert.NoError(t, err)
 }
+
+func TestStop_CloseUpdatesChannelOnceUpdatesAreHandedOff(t *testing.T) {
+	httpClient := &mockHttpClient{}
+	httpClient.On("Do", mock.Anything).Return(newGetUpdatesResponse(1), nil).Once()
+	httpClient.On("Do", mock.Anything).Return(nil, errors.New("fails"))
+
+	poller, _ := NewPoller(&Config{Token: "test"}, httpClient)
+	err := poller.start(context.Background())
+	assert.NoError(t, err)
+
+	updates := poller.getUpdatesChannel()
+	update := <-updates
+	assert.Equal(t, 1, update.ID)
+
+	poller.stop()
+
+	_, open := <-updates
+	assert.False(t, open)
+}
+
+func TestStop_ConfirmOffsetOfHandedOffUpdates(t *testing.T) {
+	httpClient, requests := newRecordingHttpClient(`{"ok": true, "result": [{"update_id": 1}, {"update_id": 2}]}`)
+
+	poller, _ := NewPoller(
+		&Config{Token: "test", PollingMode: PollingModeInterval, PollingIntervalMS: 60000},
+		httpClient,
+	)
+	_ = poller.start(context.Background())
+
+	updates := poller.getUpdatesChannel()
+	<-updates
+	<-updates
+	poller.stop()
+
+	assert.Len(t, requests[endpointGetUpdates], 2)
+	var request getUpdatesRequest
+	_ = json.Unmarshal([]byte(requests[endpointGetUpdates][1]), &request)
+	assert.Equal(t, 3, request.Offset)
+	assert.Equal(t, 0, request.Timeout)
+}
+
+func TestStop_ConfirmOffsetOfAcknowledgedUpdates(t *testing.T) {
+	httpClient, requests := newRecordingHttpClient(`{"ok": true, "result": [{"update_id": 1}]}`)
+
+	poller, _ := NewPoller(&Config{
+		Token:             "test",
+		PollingMode:       PollingModeInterval,
+		PollingIntervalMS: 60000,
+		OffsetCommitMode:  OffsetCommitOnAcknowledge,
+	}, httpClient)
+	_ = poller.start(context.Background())
+
+	poller.acknowledge(<-poller.getUpdatesChannel())
+	poller.stop()
+
+	assert.Len(t, requests[endpointGetUpdates], 2)
+	assert.Contains(t, requests[endpointGetUpdates][1], `"offset":2`)
+}
+
+func TestStart_PollAgainAsSoonAsLongPollReturns(t *testing.T) {
+	var calls atomic.Int32
+	httpClient := httpClientFunc(func(request *http.Request) (*http.Response, error) {
+		calls.Add(1)
+		return newResponse(http.StatusOK, `{"ok": true, "result": []}`), nil
+	})
+
+	poller, _ := NewPoller(&Config{Token: "test"}, httpClient)
+	_ = poller.start(context.Background())
+	defer poller.stop()
+
+	assert.Eventually(t, func() bool { return calls.Load() >= 3 }, 500*time.Millisecond, 10*time.Millisecond)
+}
+
+func TestStart_PollOnIntervalIfConfigured(t *testing.T) {
+	var calls atomic.Int32
+	httpClient := httpClientFunc(func(request *http.Request) (*http.Response, error) {
+		calls.Add(1)
+		return newResponse(http.StatusOK, `{"ok": true, "result": []}`), nil
+	})
+
+	poller, _ := NewPoller(&Config{Token: "test", PollingMode: PollingModeInterval, PollingIntervalMS: 1000}, httpClient)
+	_ = poller.start(context.Background())
+
+	time.Sleep(200 * time.Millisecond)
+	poller.stop()
+
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func TestStart_ReturnErrorIfPollingIntervalIsInvalid(t *testing.T) {
+	poller, _ := NewPoller(&Config{Token: "test", PollingMode: PollingModeInterval}, &mockHttpClient{})
+	err := poller.start(context.Background())
+
+	assert.Error(t, err)
+}
+
+func TestPoll_HandOffUpdatesInOrder(t *testing.T) {
+	httpClient := httpClientFunc(func(request *http.Request) (*http.Response, error) {
+		return newGetUpdatesResponse(3, 1, 2), nil
+	})
+
+	poller, _ := NewPoller(&Config{Token: "test"}, httpClient)
+
+	errs := make(chan error, 1)
+	go func() { errs <- poller.poll(context.Background()) }()
+
+	for _, expectedID := range []int{1, 2, 3} {
+		update := <-poller.updatesChan
+		assert.Equal(t, expectedID, update.ID)
+	}
+
+	assert.NoError(t, <-errs)
+	assert.Equal(t, 4, poller.offset)
+}
+
+func TestPoll_SkipUpdatesFromOverlappingBatches(t *testing.T) {
+	batches := [][]int{{1, 2}, {2, 3}}
+	httpClient := httpClientFunc(func(request *http.Request) (*http.Response, error) {
+		batch := batches[0]
+		batches = batches[1:]
+		return newGetUpdatesResponse(batch...), nil
+	})
+
+	poller, _ := NewPoller(&Config{Token: "test"}, httpClient)
+
+	var received []int
+	for i := 0; i < 2; i++ {
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			_ = poller.poll(context.Background())
+		}()
+
+	receive:
+		for {
+			select {
+			case update := <-poller.updatesChan:
+				received = append(received, update.ID)
+			case <-done:
+				break receive
+			}
+		}
+	}
+
+	assert.Equal(t, []int{1, 2, 3}, received)
+	assert.Equal(t, 4, poller.offset)
+}
+
+func TestPoll_DontAdvanceOffsetPastUpdatesNotHandedOff(t *testing.T) {
+	httpClient := httpClientFunc(func(request *http.Request) (*http.Response, error) {
+		return newGetUpdatesResponse(1, 2), nil
+	})
+
+	poller, _ := NewPoller(&Config{Token: "test"}, httpClient)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errs := make(chan error, 1)
+	go func() { errs <- poller.poll(ctx) }()
+
+	<-poller.updatesChan
+	cancel()
+
+	assert.ErrorIs(t, <-errs, context.Canceled)
+	assert.Equal(t, 2, poller.offset)
+}
+
+func TestPoll_AdvanceOffsetOnceUpdatesAreAcknowledged(t *testing.T) {
+	httpClient := httpClientFunc(func(request *http.Request) (*http.Response, error) {
+		return newGetUpdatesResponse(1, 2), nil
+	})
+
+	poller, _ := NewPoller(&Config{Token: "test", OffsetCommitMode: OffsetCommitOnAcknowledge}, httpClient)
+
+	errs := make(chan error, 1)
+	go func() { errs <- poller.poll(context.Background()) }()
+
+	first := <-poller.updatesChan
+	second := <-poller.updatesChan
+
+	poller.acknowledge(second)
+	select {
+	case <-errs:
+		t.Fatal("poll returned before every update was acknowledged")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	poller.acknowledge(first)
+
+	assert.NoError(t, <-errs)
+	assert.Equal(t, 3, poller.offset)
+}
+
+func TestNewPoller_ReturnErrorIfOffsetCommitModeIsInvalid(t *testing.T) {
+	poller, err := NewPoller(&Config{Token: "test", OffsetCommitMode: "invalid"}, nil)
+
+	assert.Nil(t, poller)
+	assert.Equal(t, errInvalidOffsetCommitMode, err)
+}
+
+func TestStart_DeliverOverlappingBatchesExactlyOnceAndInOrder(t *testing.T) {
+	for _, commitMode := range []string{OffsetCommitOnHandoff, OffsetCommitOnAcknowledge} {
+		t.Run(commitMode, func(t *testing.T) {
+			const lastUpdateID = 50
+
+			// simulates a server that re-sends part of the previous batch alongside new updates.
+			httpClient := httpClientFunc(func(request *http.Request) (*http.Response, error) {
+				var body getUpdatesRequest
+				_ = json.NewDecoder(request.Body).Decode(&body)
+
+				var ids []int
+				for id := body.Offset - 2; id < body.Offset+5 && id <= lastUpdateID; id++ {
+					if id > 0 {
+						ids = append(ids, id)
+					}
+				}
+				return newGetUpdatesResponse(ids...), nil
+			})
+
+			poller, _ := NewPoller(&Config{Token: "test", OffsetCommitMode: commitMode}, httpClient)
+			_ = poller.start(context.Background())
+
+			var received []int
+			var acknowledged sync.WaitGroup
+			for update := range poller.getUpdatesChannel() {
+				received = append(received, update.ID)
+
+				acknowledged.Add(1)
+				go func(u *Update) {
+					defer acknowledged.Done()
+					poller.acknowledge(u)
+				}(update)
+
+				if update.ID == lastUpdateID {
+					break
+				}
+			}
+
+			acknowledged.Wait()
+			poller.stop()
+
+			assert.Len(t, received, lastUpdateID)
+			for i, id := range received {
+				assert.Equal(t, i+1, id)
+			}
+		})
+	}
+}
+
+func newGetUpdatesResponse(ids ...int) *http.Response {
+	updates := make([]*Update, 0, len(ids))
+	for _, id := range ids {
+		updates = append(updates, &Update{ID: id})
+	}
+	result, _ := json.Marshal(updates)
+	responseJson, _ := json.Marshal(apiResponse{Ok: true, Result: result})
+
+	return newResponse(http.StatusOK, string(responseJson))
+}
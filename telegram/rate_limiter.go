@@ -0,0 +1,226 @@
+package telegram // import "heytobi.dev/fuse/telegram"
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+)
+
+const (
+	// Telegram's flood limits for sending messages.
+	// See https://core.telegram.org/bots/faq#my-bot-is-hitting-limits-how-do-i-avoid-this
+	globalMessagesPerSecond      = 30
+	privateChatMessagesPerSecond = 1
+	groupMessagesPerMinute       = 20
+
+	// groupMessagesBurst is the number of messages that can be sent to a group at once. The rate at which messages can
+	// be sent afterwards is lowered accordingly, so that no more than groupMessagesPerMinute are sent in any minute.
+	groupMessagesBurst = 5
+
+	// idleBucketsSweepInterval is how often buckets of chats that haven't been messaged recently are discarded.
+	idleBucketsSweepInterval = time.Minute
+)
+
+// RateLimiter paces requests sending messages to Telegram. Wait is called before every such request and blocks until
+// the request can be made, or until the given context is done, in which case the request is not made and the context's
+// error is returned.
+type RateLimiter interface {
+	Wait(ctx context.Context, chatID int64) error
+}
+
+// RateLimiterStats describes the requests waiting on a FloodLimiter.
+type RateLimiterStats struct {
+	// Queued is the total number of requests waiting.
+	Queued int
+	// QueuedByChat is the number of requests waiting, by chat ID. Chats with no waiting request are omitted.
+	QueuedByChat map[int64]int
+}
+
+// FloodLimiter is a RateLimiter enforcing Telegram's documented flood limits: 30 messages per second overall, 1 message
+// per second in a private chat and 20 messages per minute in a group or channel. Up to 5 messages can be sent to a
+// group or channel at once, after which they're spaced 4 seconds apart.
+//
+// Each chat has its own queue, served in order, so that a busy chat doesn't delay messages to other chats beyond the
+// overall limit.
+type FloodLimiter struct {
+	mu        sync.Mutex
+	global    *bucket
+	chats     map[int64]*bucket
+	queued    map[int64]int
+	lastSweep time.Time
+}
+
+// NewFloodLimiter initializes a FloodLimiter.
+func NewFloodLimiter() *FloodLimiter {
+	return &FloodLimiter{
+		global:    newBucket(globalMessagesPerSecond, globalMessagesPerSecond),
+		chats:     make(map[int64]*bucket),
+		queued:    make(map[int64]int),
+		lastSweep: time.Now(),
+	}
+}
+
+// Wait blocks until a message can be sent to the given chat. Private chats have positive IDs, while groups and
+// channels have negative IDs.
+func (l *FloodLimiter) Wait(ctx context.Context, chatID int64) error {
+	l.mu.Lock()
+	now := time.Now()
+	l.sweep(now)
+
+	chat, ok := l.chats[chatID]
+	if !ok {
+		chat = newChatBucket(chatID)
+		l.chats[chatID] = chat
+	}
+	chatReservation := chat.reserve(now)
+	l.queued[chatID]++
+	l.mu.Unlock()
+
+	defer l.dequeue(chatID)
+
+	// the overall limit only applies once it's the chat's turn, so that waiting messages for busy chats don't hold
+	// back other chats.
+	err := wait(ctx, chatReservation.ready.Sub(now))
+	if err != nil {
+		l.cancel(chat, chatReservation)
+		return err
+	}
+
+	l.mu.Lock()
+	now = time.Now()
+	globalReservation := l.global.reserve(now)
+	l.mu.Unlock()
+
+	err = wait(ctx, globalReservation.ready.Sub(now))
+	if err != nil {
+		l.cancel(l.global, globalReservation)
+		return err
+	}
+
+	return nil
+}
+
+// Stats returns the number of requests currently waiting.
+func (l *FloodLimiter) Stats() RateLimiterStats {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	stats := RateLimiterStats{QueuedByChat: make(map[int64]int, len(l.queued))}
+	for chatID, queued := range l.queued {
+		stats.Queued += queued
+		stats.QueuedByChat[chatID] = queued
+	}
+
+	return stats
+}
+
+func (l *FloodLimiter) cancel(b *bucket, r reservation) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	b.cancel(r)
+}
+
+func (l *FloodLimiter) dequeue(chatID int64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.queued[chatID]--
+	if l.queued[chatID] == 0 {
+		delete(l.queued, chatID)
+	}
+}
+
+// sweep discards the buckets of chats with no waiting request, whose buckets are full again.
+func (l *FloodLimiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < idleBucketsSweepInterval {
+		return
+	}
+	l.lastSweep = now
+
+	for chatID, chat := range l.chats {
+		if l.queued[chatID] == 0 && chat.isFull(now) {
+			delete(l.chats, chatID)
+		}
+	}
+}
+
+// bucket is a token bucket handing out reservations, a reservation being the time at which a token is available.
+// Reservations are made in order, so requests are served in the order they are made.
+type bucket struct {
+	interval time.Duration
+	burst    int
+	// next is the time at which a token is available if none is left. It lags behind the current time when tokens
+	// have been accumulated, by up to burst intervals.
+	next time.Time
+	// cancelled holds the slots of cancelled reservations whose tokens can't be given back yet, since reservations
+	// were made after them.
+	cancelled []time.Time
+}
+
+// reservation is a token taken from a bucket.
+type reservation struct {
+	// slot is the position of the reservation in the sequence of reservations made by the bucket.
+	slot time.Time
+	// ready is the time at which the token is available.
+	ready time.Time
+}
+
+func newBucket(perSecond float64, burst int) *bucket {
+	return &bucket{
+		interval: time.Duration(float64(time.Second) / perSecond),
+		burst:    burst,
+	}
+}
+
+func newChatBucket(chatID int64) *bucket {
+	if chatID < 0 {
+		return newBucket((groupMessagesPerMinute-groupMessagesBurst)/60.0, groupMessagesBurst)
+	}
+
+	return newBucket(privateChatMessagesPerSecond, 1)
+}
+
+// reserve takes a token, returning a reservation holding the time at which it's available.
+func (b *bucket) reserve(now time.Time) reservation {
+	earliest := now.Add(-time.Duration(b.burst-1) * b.interval)
+	if b.next.Before(earliest) {
+		b.next = earliest
+	}
+
+	// cancelled reservations older than the accumulated tokens can't be given back anymore.
+	b.cancelled = slices.DeleteFunc(b.cancelled, func(slot time.Time) bool {
+		return slot.Before(earliest)
+	})
+
+	r := reservation{slot: b.next, ready: b.next}
+	b.next = b.next.Add(b.interval)
+
+	if r.ready.Before(now) {
+		r.ready = now
+	}
+
+	return r
+}
+
+// cancel gives back the token of a reservation that won't be used. Since later reservations were scheduled after it,
+// the token is only given back once every reservation made after it has been cancelled too.
+func (b *bucket) cancel(r reservation) {
+	b.cancelled = append(b.cancelled, r.slot)
+
+	for {
+		last := b.next.Add(-b.interval)
+		i := slices.IndexFunc(b.cancelled, last.Equal)
+		if i < 0 {
+			return
+		}
+
+		b.cancelled = slices.Delete(b.cancelled, i, i+1)
+		b.next = last
+	}
+}
+
+func (b *bucket) isFull(now time.Time) bool {
+	return !b.next.After(now.Add(-time.Duration(b.burst-1) * b.interval))
+}
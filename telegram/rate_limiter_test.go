@@ -0,0 +1,160 @@
+package telegram
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBucketReserve_AllowBurstThenSpaceReservations(t *testing.T) {
+	now := time.Now()
+	bucket := newBucket(10, 3)
+
+	assert.Equal(t, now, bucket.reserve(now).ready)
+	assert.Equal(t, now, bucket.reserve(now).ready)
+	assert.Equal(t, now, bucket.reserve(now).ready)
+	assert.Equal(t, now.Add(100*time.Millisecond), bucket.reserve(now).ready)
+	assert.Equal(t, now.Add(200*time.Millisecond), bucket.reserve(now).ready)
+}
+
+func TestBucketReserve_RefillOverTime(t *testing.T) {
+	now := time.Now()
+	bucket := newBucket(1, 1)
+
+	assert.Equal(t, now, bucket.reserve(now).ready)
+	assert.False(t, bucket.isFull(now))
+	assert.True(t, bucket.isFull(now.Add(time.Second)))
+	assert.Equal(t, now.Add(2*time.Second), bucket.reserve(now.Add(2*time.Second)).ready)
+}
+
+func TestBucketCancel_GiveBackTokenOfLastReservation(t *testing.T) {
+	now := time.Now()
+	bucket := newBucket(1, 1)
+
+	bucket.reserve(now)
+	bucket.cancel(bucket.reserve(now))
+
+	assert.Equal(t, now.Add(time.Second), bucket.reserve(now).ready)
+}
+
+func TestBucketCancel_GiveBackTokensOnceLaterReservationsAreCancelled(t *testing.T) {
+	now := time.Now()
+	bucket := newBucket(1, 1)
+
+	bucket.reserve(now)
+	first := bucket.reserve(now)
+	second := bucket.reserve(now)
+
+	bucket.cancel(first)
+	assert.Equal(t, now.Add(3*time.Second), bucket.next)
+
+	bucket.cancel(second)
+	assert.Equal(t, now.Add(time.Second), bucket.next)
+	assert.Empty(t, bucket.cancelled)
+}
+
+func TestNewChatBucket_ApplyGroupAndPrivateChatLimits(t *testing.T) {
+	assert.Equal(t, time.Second, newChatBucket(42).interval)
+	assert.Equal(t, 1, newChatBucket(42).burst)
+	assert.Equal(t, 4*time.Second, newChatBucket(-42).interval)
+	assert.Equal(t, 5, newChatBucket(-42).burst)
+}
+
+func TestFloodLimiterWait_PaceMessagesToTheSameChat(t *testing.T) {
+	limiter := NewFloodLimiter()
+	assert.NoError(t, limiter.Wait(context.Background(), 42))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	assert.ErrorIs(t, limiter.Wait(ctx, 42), context.DeadlineExceeded)
+}
+
+func TestFloodLimiterWait_ReleaseReservationIfContextIsDone(t *testing.T) {
+	limiter := NewFloodLimiter()
+	assert.NoError(t, limiter.Wait(context.Background(), 42))
+	next := limiter.chats[42].next
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.ErrorIs(t, limiter.Wait(ctx, 42), context.Canceled)
+
+	assert.Equal(t, next, limiter.chats[42].next)
+}
+
+func TestFloodLimiterWait_AllowBurstInGroups(t *testing.T) {
+	limiter := NewFloodLimiter()
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	for i := 0; i < groupMessagesBurst; i++ {
+		assert.NoError(t, limiter.Wait(ctx, -42))
+	}
+	assert.ErrorIs(t, limiter.Wait(ctx, -42), context.DeadlineExceeded)
+}
+
+func TestFloodLimiterWait_DoNotDelayOtherChats(t *testing.T) {
+	limiter := NewFloodLimiter()
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	assert.NoError(t, limiter.Wait(ctx, -1))
+	assert.NoError(t, limiter.Wait(ctx, -2))
+	assert.NoError(t, limiter.Wait(ctx, 3))
+}
+
+func TestFloodLimiterStats_CountWaitingRequestsByChat(t *testing.T) {
+	limiter := NewFloodLimiter()
+	assert.NoError(t, limiter.Wait(context.Background(), 1))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- limiter.Wait(ctx, 1)
+	}()
+
+	assert.Eventually(t, func() bool {
+		return limiter.Stats().Queued == 1
+	}, time.Second, time.Millisecond)
+	assert.Equal(t, map[int64]int{1: 1}, limiter.Stats().QueuedByChat)
+
+	cancel()
+	assert.ErrorIs(t, <-done, context.Canceled)
+	assert.Equal(t, 0, limiter.Stats().Queued)
+}
+
+func TestExecute_WaitForRateLimiterBeforeSendingMessages(t *testing.T) {
+	httpClient, _ := newRecordingHttpClient(`{"ok": true, "result": true}`)
+	limiter := &recordingRateLimiter{}
+	executor := newExecutor(httpClient, testApiUrlFmt, testToken, nil)
+	executor.rateLimiter = limiter
+
+	_ = executor.execute(context.Background(), endpointSendMessage, &SendMessageRequest{ChatID: 42}, nil)
+	_ = executor.execute(context.Background(), endpointAnswerCallbackQuery, &AnswerCallbackQueryRequest{}, nil)
+
+	assert.Equal(t, []int64{42}, limiter.chatIDs)
+}
+
+func TestExecute_DoNotSendMessageIfRateLimiterFails(t *testing.T) {
+	httpClient, requests := newRecordingHttpClient(`{"ok": true, "result": true}`)
+	executor := newExecutor(httpClient, testApiUrlFmt, testToken, nil)
+	executor.rateLimiter = NewFloodLimiter()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := executor.execute(ctx, endpointSendMessage, &SendMessageRequest{ChatID: 42}, nil)
+
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Empty(t, requests[endpointSendMessage])
+}
+
+type recordingRateLimiter struct {
+	chatIDs []int64
+}
+
+func (l *recordingRateLimiter) Wait(ctx context.Context, chatID int64) error {
+	l.chatIDs = append(l.chatIDs, chatID)
+	return nil
+}
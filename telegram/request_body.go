@@ -0,0 +1,130 @@
+package telegram // import "heytobi.dev/fuse/telegram"
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"mime/multipart"
+	"sort"
+
+	"github.com/pkg/errors"
+)
+
+// requestBody is the body of a Bot API request. Requests uploading files are sent as multipart/form-data, other
+// requests are sent as JSON.
+type requestBody struct {
+	json  []byte
+	files map[string]*InputFile
+}
+
+func newRequestBody(params any) (*requestBody, error) {
+	bodyJson, err := json.Marshal(params)
+	if err != nil {
+		return nil, err
+	}
+
+	body := &requestBody{json: bodyJson}
+
+	if uploader, ok := params.(uploader); ok {
+		for name, file := range uploader.files() {
+			if file == nil || !file.needsUpload() {
+				continue
+			}
+
+			if body.files == nil {
+				body.files = make(map[string]*InputFile)
+			}
+			body.files[name] = file
+		}
+	}
+
+	return body, nil
+}
+
+// isReplayable returns whether the body can be sent again, if the request has to be retried.
+func (b *requestBody) isReplayable() bool {
+	for _, file := range b.files {
+		if !file.isReplayable() {
+			return false
+		}
+	}
+
+	return true
+}
+
+// reader returns a reader streaming the body, along with its content type. Files are read as the body is consumed.
+func (b *requestBody) reader() (io.ReadCloser, string, error) {
+	if len(b.files) == 0 {
+		return io.NopCloser(bytes.NewReader(b.json)), "application/json", nil
+	}
+
+	var fields map[string]json.RawMessage
+	err := json.Unmarshal(b.json, &fields)
+	if err != nil {
+		return nil, "", errors.Wrap(err, "failed to decode multipart fields")
+	}
+
+	pipeReader, pipeWriter := io.Pipe()
+	writer := multipart.NewWriter(pipeWriter)
+
+	go func() {
+		_ = pipeWriter.CloseWithError(b.writeMultipart(writer, fields))
+	}()
+
+	return pipeReader, writer.FormDataContentType(), nil
+}
+
+func (b *requestBody) writeMultipart(writer *multipart.Writer, fields map[string]json.RawMessage) error {
+	for _, name := range sortedKeys(fields) {
+		value := fields[name]
+		if _, isFile := b.files[name]; isFile || string(value) == "null" {
+			continue
+		}
+
+		// strings are sent as is, any other value is sent JSON encoded.
+		var text string
+		if json.Unmarshal(value, &text) != nil {
+			text = string(value)
+		}
+
+		err := writer.WriteField(name, text)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, name := range sortedKeys(b.files) {
+		err := writeFile(writer, name, b.files[name])
+		if err != nil {
+			return errors.Wrap(err, "failed to upload "+name)
+		}
+	}
+
+	return writer.Close()
+}
+
+func writeFile(writer *multipart.Writer, name string, file *InputFile) error {
+	content, err := file.open()
+	if err != nil {
+		return err
+	}
+	defer content.Close()
+
+	part, err := writer.CreateFormFile(name, file.name)
+	if err != nil {
+		return err
+	}
+
+	_, err = io.Copy(part, content)
+	return err
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
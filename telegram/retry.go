@@ -0,0 +1,108 @@
+package telegram // import "heytobi.dev/fuse/telegram"
+
+import (
+	"context"
+	"math/rand"
+	"net/http"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	defaultRetryMaxAttempts    = 3
+	defaultRetryInitialBackoff = 500 * time.Millisecond
+	defaultRetryMaxBackoff     = 30 * time.Second
+)
+
+// RetryPolicy defines how failed Bot API requests are retried.
+//
+// Requests that were rejected because of flood limits, i.e. with a 429 response, are always retried once the delay
+// given by Telegram's retry_after parameter has elapsed. Requests that failed because of a network error or a 5xx
+// response are only retried if they are idempotent, since Telegram might have performed them already, e.g. a message
+// might have been sent although the response was lost, so methods sending content are not retried in this case.
+//
+// Between other attempts, the delay grows exponentially from InitialBackoff up to MaxBackoff, with random jitter.
+// Retries never outlive the context of the request, if the next attempt can't be made before the context's deadline,
+// the last error is returned.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of times a request is made, including the first attempt. Defaults to 3.
+	MaxAttempts int
+	// InitialBackoff is the delay before the first retry. Defaults to 500ms.
+	InitialBackoff time.Duration
+	// MaxBackoff caps the delay between attempts, it doesn't apply to delays requested through retry_after. Defaults
+	// to 30s.
+	MaxBackoff time.Duration
+}
+
+func (p *RetryPolicy) maxAttempts() int {
+	if p == nil {
+		return 1
+	}
+
+	if p.MaxAttempts < 1 {
+		return defaultRetryMaxAttempts
+	}
+
+	return p.MaxAttempts
+}
+
+// shouldRetry returns whether a request to the given endpoint that failed with the given error can be retried.
+func (p *RetryPolicy) shouldRetry(endpoint string, err error) bool {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		if apiErr.ErrorCode == http.StatusTooManyRequests {
+			return true
+		}
+
+		return apiErr.ErrorCode >= http.StatusInternalServerError && idempotentEndpoints[endpoint]
+	}
+
+	// any other error happened while making the request, e.g. the connection failed or timed out.
+	return idempotentEndpoints[endpoint]
+}
+
+// backoff returns the delay before the given retry, retries being numbered from 1.
+func (p *RetryPolicy) backoff(retry int, err error) time.Duration {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.ResponseParameters != nil && apiErr.ResponseParameters.RetryAfter > 0 {
+		return time.Duration(apiErr.ResponseParameters.RetryAfter) * time.Second
+	}
+
+	initialBackoff, maxBackoff := p.InitialBackoff, p.MaxBackoff
+	if initialBackoff <= 0 {
+		initialBackoff = defaultRetryInitialBackoff
+	}
+	if maxBackoff <= 0 {
+		maxBackoff = defaultRetryMaxBackoff
+	}
+
+	delay := maxBackoff
+	if retry < 32 && initialBackoff<<(retry-1) < maxBackoff {
+		delay = initialBackoff << (retry - 1)
+	}
+
+	// half of the delay is random so that clients failing together don't retry together.
+	return delay/2 + time.Duration(rand.Int63n(int64(delay/2)+1))
+}
+
+// wait blocks for the given delay, unless the given context is done before or is due to expire in the meantime.
+func wait(ctx context.Context, delay time.Duration) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < delay {
+		return context.DeadlineExceeded
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,139 @@
+package telegram
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var testRetryPolicy = &RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond, MaxBackoff: 10 * time.Millisecond}
+
+const (
+	testServerError = `{"ok": false, "error_code": 500}`
+	testFloodError  = `{"ok": false, "error_code": 429}`
+)
+
+func TestExecute_RetryIdempotentRequestOnServerError(t *testing.T) {
+	httpClient := &mockHttpClient{}
+	for i := 0; i < 2; i++ {
+		httpClient.On("Do", mock.Anything).Return(newResponse(http.StatusInternalServerError, testServerError), nil).Once()
+	}
+	httpClient.On("Do", mock.Anything).Return(newResponse(http.StatusOK, `{"ok": true, "result": true}`), nil).Once()
+	executor := newExecutor(httpClient, testApiUrlFmt, testToken, testRetryPolicy)
+
+	var deleted bool
+	err := executor.execute(context.Background(), endpointDeleteWebhook, deleteWebhookRequest{}, &deleted)
+
+	assert.NoError(t, err)
+	assert.True(t, deleted)
+	httpClient.AssertNumberOfCalls(t, "Do", 3)
+}
+
+func TestExecute_RetryIdempotentRequestOnNetworkError(t *testing.T) {
+	httpClient := &mockHttpClient{}
+	httpClient.On("Do", mock.Anything).Return(nil, errors.New("connection reset")).Once()
+	httpClient.On("Do", mock.Anything).Return(newResponse(http.StatusOK, `{"ok": true, "result": []}`), nil).Once()
+	executor := newExecutor(httpClient, testApiUrlFmt, testToken, testRetryPolicy)
+
+	err := executor.execute(context.Background(), endpointGetUpdates, getUpdatesRequest{}, nil)
+
+	assert.NoError(t, err)
+	httpClient.AssertNumberOfCalls(t, "Do", 2)
+}
+
+func TestExecute_DoNotRetryNonIdempotentRequestOnServerError(t *testing.T) {
+	httpClient := &mockHttpClient{}
+	httpClient.On("Do", mock.Anything).Return(newResponse(http.StatusBadGateway, "Bad Gateway"), nil).Once()
+	executor := newExecutor(httpClient, testApiUrlFmt, testToken, testRetryPolicy)
+
+	err := executor.execute(context.Background(), endpointSendMessage, &SendMessageRequest{}, nil)
+
+	assert.Error(t, err)
+	httpClient.AssertNumberOfCalls(t, "Do", 1)
+}
+
+func TestExecute_RetryNonIdempotentRequestOnFloodLimit(t *testing.T) {
+	httpClient := &mockHttpClient{}
+	httpClient.On("Do", mock.Anything).Return(newResponse(http.StatusTooManyRequests, testFloodError), nil).Once()
+	httpClient.On("Do", mock.Anything).Return(newResponse(http.StatusOK, `{"ok": true, "result": true}`), nil).Once()
+	executor := newExecutor(httpClient, testApiUrlFmt, testToken, testRetryPolicy)
+
+	err := executor.execute(context.Background(), endpointSendMessage, &SendMessageRequest{}, nil)
+
+	assert.NoError(t, err)
+	httpClient.AssertNumberOfCalls(t, "Do", 2)
+}
+
+func TestExecute_StopRetryingAfterMaxAttempts(t *testing.T) {
+	httpClient := &mockHttpClient{}
+	for i := 0; i < testRetryPolicy.MaxAttempts; i++ {
+		httpClient.On("Do", mock.Anything).Return(newResponse(http.StatusInternalServerError, testServerError), nil).Once()
+	}
+	executor := newExecutor(httpClient, testApiUrlFmt, testToken, testRetryPolicy)
+
+	err := executor.execute(context.Background(), endpointDeleteWebhook, deleteWebhookRequest{}, nil)
+
+	var apiErr *APIError
+	assert.True(t, errors.As(err, &apiErr))
+	httpClient.AssertNumberOfCalls(t, "Do", 3)
+}
+
+func TestExecute_DoNotRetryWithoutRetryPolicy(t *testing.T) {
+	httpClient := &mockHttpClient{}
+	httpClient.On("Do", mock.Anything).Return(newResponse(http.StatusTooManyRequests, testFloodError), nil).Once()
+	executor := newExecutor(httpClient, testApiUrlFmt, testToken, nil)
+
+	err := executor.execute(context.Background(), endpointSendMessage, &SendMessageRequest{}, nil)
+
+	assert.Error(t, err)
+	httpClient.AssertNumberOfCalls(t, "Do", 1)
+}
+
+func TestExecute_DoNotRetryPastContextDeadline(t *testing.T) {
+	httpClient := &mockHttpClient{}
+	httpClient.On("Do", mock.Anything).Return(newResponse(
+		http.StatusTooManyRequests,
+		`{"ok": false, "error_code": 429, "parameters": {"retry_after": 5}}`,
+	), nil).Once()
+	executor := newExecutor(httpClient, testApiUrlFmt, testToken, testRetryPolicy)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	start := time.Now()
+	err := executor.execute(ctx, endpointSendMessage, &SendMessageRequest{}, nil)
+
+	var apiErr *APIError
+	assert.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, 5, apiErr.ResponseParameters.RetryAfter)
+	httpClient.AssertNumberOfCalls(t, "Do", 1)
+	assert.Less(t, time.Since(start), time.Second)
+}
+
+func TestBackoff_HonorRetryAfter(t *testing.T) {
+	err := &APIError{ErrorCode: http.StatusTooManyRequests, ResponseParameters: &ResponseParameters{RetryAfter: 42}}
+
+	assert.Equal(t, 42*time.Second, testRetryPolicy.backoff(1, err))
+}
+
+func TestBackoff_GrowExponentiallyWithJitter(t *testing.T) {
+	policy := &RetryPolicy{InitialBackoff: 100 * time.Millisecond, MaxBackoff: time.Second}
+	err := errors.New("connection reset")
+
+	for retry, expected := range map[int]time.Duration{
+		1:  100 * time.Millisecond,
+		2:  200 * time.Millisecond,
+		3:  400 * time.Millisecond,
+		4:  800 * time.Millisecond,
+		5:  time.Second,
+		64: time.Second,
+	} {
+		delay := policy.backoff(retry, err)
+		assert.GreaterOrEqual(t, delay, expected/2)
+		assert.LessOrEqual(t, delay, expected)
+	}
+}
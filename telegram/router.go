@@ -0,0 +1,172 @@
+package telegram // import "heytobi.dev/fuse/telegram"
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Filter reports whether an update matches a condition. Filters can be composed with And, Or and Not.
+type Filter func(update *Update) bool
+
+// Route defines a handler for updates matching a set of filters.
+type Route struct {
+	// Filters are the conditions an update must match for the route to handle it, a route without filters matches
+	// every update.
+	Filters []Filter
+	// Handler handles the updates matching the route.
+	Handler HandlerFunc
+	// Priority determines the order in which routes are evaluated, routes with a higher priority are evaluated first.
+	Priority int
+}
+
+// RegisterRoute registers the given route. Routes are evaluated in descending order of priority, routes with the same
+// priority being evaluated in the order they were registered, the first matching route handles the update.
+// Messages are only matched against routes if they don't match a command handler, updates that don't match any route
+// are passed to the default handler, or the handler registered for their type.
+func (b *Bot) RegisterRoute(route *Route) error {
+	if route == nil {
+		return errNilRoute
+	}
+
+	if route.Handler == nil {
+		return errNilRouteHandler
+	}
+
+	b.routes = append(b.routes, route)
+	sort.SliceStable(b.routes, func(i, j int) bool {
+		return b.routes[i].Priority > b.routes[j].Priority
+	})
+
+	return nil
+}
+
+// RegisterRoute registers the given route, its handler is wrapped by the group's middleware.
+// See Bot.RegisterRoute.
+func (g *Group) RegisterRoute(route *Route) error {
+	if route == nil {
+		return errNilRoute
+	}
+
+	if route.Handler == nil {
+		return errNilRouteHandler
+	}
+
+	return g.bot.RegisterRoute(&Route{
+		Filters:  route.Filters,
+		Handler:  g.wrap(route.Handler),
+		Priority: route.Priority,
+	})
+}
+
+// matchRoute returns the first route matching the given update, or nil if there is none.
+func (b *Bot) matchRoute(update *Update) *Route {
+	for _, route := range b.routes {
+		if And(route.Filters...)(update) {
+			return route
+		}
+	}
+
+	return nil
+}
+
+// And returns a filter matching updates that match all the given filters.
+func And(filters ...Filter) Filter {
+	return func(update *Update) bool {
+		for _, filter := range filters {
+			if !filter(update) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// Or returns a filter matching updates that match at least one of the given filters.
+func Or(filters ...Filter) Filter {
+	return func(update *Update) bool {
+		for _, filter := range filters {
+			if filter(update) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// Not returns a filter matching updates that don't match the given filter.
+func Not(filter Filter) Filter {
+	return func(update *Update) bool {
+		return !filter(update)
+	}
+}
+
+// MatchRegexp returns a filter matching updates whose text matches the given regular expression. The text of an update
+// is the text or caption of a message, the data of a callback query or the query of an inline query.
+func MatchRegexp(pattern *regexp.Regexp) Filter {
+	return func(update *Update) bool {
+		text, hasText := updateText(update)
+		return hasText && pattern.MatchString(text)
+	}
+}
+
+// HasPrefix returns a filter matching updates whose text starts with the given prefix.
+// See MatchRegexp for what the text of an update is.
+func HasPrefix(prefix string) Filter {
+	return func(update *Update) bool {
+		text, hasText := updateText(update)
+		return hasText && strings.HasPrefix(text, prefix)
+	}
+}
+
+// HasContentType returns a filter matching messages with one of the given content types, e.g. ContentTypePhoto.
+func HasContentType(contentTypes ...string) Filter {
+	return func(update *Update) bool {
+		message := update.effectiveMessage()
+		return message != nil && contains(contentTypes, message.ContentType())
+	}
+}
+
+// InChatType returns a filter matching updates from chats of one of the given types, e.g. ChatTypePrivate.
+func InChatType(chatTypes ...string) Filter {
+	return func(update *Update) bool {
+		chat := update.Chat()
+		return chat != nil && contains(chatTypes, chat.Type)
+	}
+}
+
+// IsUpdateType returns a filter matching updates of one of the given types, e.g. UpdateTypeCallbackQuery.
+func IsUpdateType(updateTypes ...string) Filter {
+	return func(update *Update) bool {
+		return contains(updateTypes, update.Type())
+	}
+}
+
+func updateText(update *Update) (string, bool) {
+	if message := update.effectiveMessage(); message != nil {
+		if message.Text != "" {
+			return message.Text, true
+		}
+		return message.Caption, message.Caption != ""
+	}
+
+	if update.CallbackQuery != nil {
+		return update.CallbackQuery.Data, true
+	}
+
+	if update.InlineQuery != nil {
+		return update.InlineQuery.Query, true
+	}
+
+	return "", false
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,128 @@
+package telegram
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func namedHandler(name string, handled *[]string) HandlerFunc {
+	return func(ctx context.Context, update *Update) error {
+		*handled = append(*handled, name)
+		return nil
+	}
+}
+
+func TestRegisterRoute_ReturnErrorIfRouteIsInvalid(t *testing.T) {
+	bot, _ := NewBot(&Config{Token: "test"}, &mockHttpClient{})
+
+	assert.Equal(t, errNilRoute, bot.RegisterRoute(nil))
+	assert.Equal(t, errNilRouteHandler, bot.RegisterRoute(&Route{}))
+}
+
+func TestRegisterRoute_EvaluateRoutesByPriorityThenRegistrationOrder(t *testing.T) {
+	var handled []string
+
+	bot, _ := NewBot(&Config{Token: "test"}, &mockHttpClient{})
+	_ = bot.RegisterRoute(&Route{Handler: namedHandler("first", &handled), Filters: []Filter{HasPrefix("hi")}})
+	_ = bot.RegisterRoute(&Route{Handler: namedHandler("second", &handled), Filters: []Filter{HasPrefix("hi")}})
+	_ = bot.RegisterRoute(&Route{
+		Handler:  namedHandler("priority", &handled),
+		Filters:  []Filter{HasPrefix("hi there")},
+		Priority: 1,
+	})
+
+	_ = bot.ProcessUpdate(context.Background(), &Update{Message: &Message{Text: "hi there"}})
+	_ = bot.ProcessUpdate(context.Background(), &Update{Message: &Message{Text: "hi"}})
+
+	assert.Equal(t, []string{"priority", "first"}, handled)
+}
+
+func TestRegisterRoute_PreferCommandHandlersAndFallBackToDefaultHandler(t *testing.T) {
+	var handled []string
+
+	bot, _ := NewBot(&Config{Token: "test"}, &mockHttpClient{})
+	_ = bot.RegisterHandler("/start", namedHandler("command", &handled))
+	_ = bot.RegisterDefaultHandler(namedHandler("default", &handled))
+	_ = bot.RegisterRoute(&Route{Handler: namedHandler("route", &handled), Filters: []Filter{HasPrefix("/")}})
+
+	_ = bot.ProcessUpdate(context.Background(), &Update{Message: &Message{Text: "/start"}})
+	_ = bot.ProcessUpdate(context.Background(), &Update{Message: &Message{Text: "/other"}})
+	_ = bot.ProcessUpdate(context.Background(), &Update{Message: &Message{Text: "hello"}})
+
+	assert.Equal(t, []string{"command", "route", "default"}, handled)
+}
+
+func TestRegisterRoute_RouteOtherUpdateTypes(t *testing.T) {
+	var handled []string
+
+	httpClient, _ := newRecordingHttpClient(`{"ok": true, "result": true}`)
+	bot, _ := NewBot(&Config{Token: "test"}, httpClient)
+	_ = bot.OnCallbackQuery(namedHandler("callback", &handled))
+	_ = bot.RegisterRoute(&Route{Handler: namedHandler("route", &handled), Filters: []Filter{HasPrefix("vote:")}})
+
+	_ = bot.ProcessUpdate(context.Background(), &Update{CallbackQuery: &CallbackQuery{Data: "vote:1"}})
+	_ = bot.ProcessUpdate(context.Background(), &Update{CallbackQuery: &CallbackQuery{Data: "menu"}})
+
+	assert.Equal(t, []string{"route", "callback"}, handled)
+}
+
+func TestGroup_RegisterRouteWithMiddleware(t *testing.T) {
+	var calls []string
+
+	bot, _ := NewBot(&Config{Token: "test"}, &mockHttpClient{})
+	group := bot.Group(recordingMiddleware("group", &calls))
+	_ = group.RegisterRoute(&Route{Handler: namedHandler("route", &calls)})
+
+	_ = bot.ProcessUpdate(context.Background(), &Update{Message: &Message{Text: "hello"}})
+
+	assert.Equal(t, []string{"group", "route"}, calls)
+}
+
+func TestFilters(t *testing.T) {
+	photoInGroup := &Update{Message: &Message{
+		Caption: "holiday 2023",
+		Photo:   []PhotoSize{{FileID: "photo"}},
+		Chat:    &Chat{Type: ChatTypeGroup},
+	}}
+	location := &Update{Message: &Message{
+		Location: &Location{},
+		Chat:     &Chat{Type: ChatTypePrivate},
+	}}
+	inlineQuery := &Update{InlineQuery: &InlineQuery{Query: "cats"}}
+
+	tests := []struct {
+		name     string
+		filter   Filter
+		update   *Update
+		expected bool
+	}{
+		{"regexp matches caption", MatchRegexp(regexp.MustCompile(`\d{4}`)), photoInGroup, true},
+		{"regexp matches inline query", MatchRegexp(regexp.MustCompile(`^cat`)), inlineQuery, true},
+		{"regexp needs text", MatchRegexp(regexp.MustCompile(`.*`)), location, false},
+		{"prefix", HasPrefix("holiday"), photoInGroup, true},
+		{"content type", HasContentType(ContentTypePhoto, ContentTypeDocument), photoInGroup, true},
+		{"other content type", HasContentType(ContentTypeContact), location, false},
+		{"chat type", InChatType(ChatTypeGroup, ChatTypeSupergroup), photoInGroup, true},
+		{"chat type without chat", InChatType(ChatTypePrivate), inlineQuery, false},
+		{"update type", IsUpdateType(UpdateTypeInlineQuery), inlineQuery, true},
+		{"and", And(HasContentType(ContentTypeLocation), InChatType(ChatTypePrivate)), location, true},
+		{"or", Or(HasContentType(ContentTypeContact), InChatType(ChatTypeGroup)), location, false},
+		{"not", Not(InChatType(ChatTypePrivate)), location, false},
+		{"predicate", func(update *Update) bool { return update.InlineQuery != nil }, inlineQuery, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, test.filter(test.update))
+		})
+	}
+}
+
+func TestContentType_PreferMostSpecificType(t *testing.T) {
+	assert.Equal(t, ContentTypeAnimation, (&Message{Animation: &Animation{}, Document: &Document{}}).ContentType())
+	assert.Equal(t, ContentTypeVenue, (&Message{Venue: &Venue{}, Location: &Location{}}).ContentType())
+	assert.Equal(t, "", (&Message{NewChatTitle: "title"}).ContentType())
+}
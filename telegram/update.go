@@ -1,5 +1,21 @@
 package telegram // import "heytobi.dev/fuse/telegram"
 
+// Update types, as used in the allowed_updates parameter of getUpdates and setWebhook.
+// See https://core.telegram.org/bots/api#update
+const (
+	UpdateTypeMessage            = "message"
+	UpdateTypeEditedMessage      = "edited_message"
+	UpdateTypeChannelPost        = "channel_post"
+	UpdateTypeEditedChannelPost  = "edited_channel_post"
+	UpdateTypeInlineQuery        = "inline_query"
+	UpdateTypeChosenInlineResult = "chosen_inline_result"
+	UpdateTypeCallbackQuery      = "callback_query"
+	UpdateTypeShippingQuery      = "shipping_query"
+	UpdateTypePreCheckoutQuery   = "pre_checkout_query"
+	UpdateTypePoll               = "poll"
+	UpdateTypePollAnswer         = "poll_answer"
+)
+
 // InlineQuery represents an incoming inline query.
 // See https://core.telegram.org/bots/api#inlinequery
 type InlineQuery struct {
@@ -24,7 +40,7 @@ type ChosenInlineResult struct {
 // CallbackQuery represents an incoming callback query from a callback button in an inline keyboard.
 // See https://core.telegram.org/bots/api#callbackquery
 type CallbackQuery struct {
-	ID              string   `json:"result_id"`
+	ID              string   `json:"id"`
 	From            *User    `json:"from"`
 	Message         *Message `json:"message"`
 	InlineMessageID string   `json:"inline_message_id"`
@@ -36,7 +52,7 @@ type CallbackQuery struct {
 // ShippingQuery contains information about an incoming shipping query.
 // See https://core.telegram.org/bots/api#shippingquery
 type ShippingQuery struct {
-	ID              string           `json:"result_id"`
+	ID              string           `json:"id"`
 	From            *User            `json:"from"`
 	InvoicePayload  string           `json:"invoice_payload"`
 	ShippingAddress *ShippingAddress `json:"shipping_address"`
@@ -45,7 +61,7 @@ type ShippingQuery struct {
 // PreCheckoutQuery contains information about an incoming pre-checkout query.
 // See https://core.telegram.org/bots/api#precheckoutquery
 type PreCheckoutQuery struct {
-	ID               string     `json:"result_id"`
+	ID               string     `json:"id"`
 	From             *User      `json:"from"`
 	Currency         string     `json:"currency"`
 	TotalAmount      int        `json:"total_amount"`
@@ -72,6 +88,90 @@ type Update struct {
 	// TODO add my_chat_member, chat_member, chat_join_request
 }
 
+// Type returns the type of the update, i.e. which of its optional fields is set. It returns an empty string if the
+// update type isn't supported.
+func (u *Update) Type() string {
+	switch {
+	case u.Message != nil:
+		return UpdateTypeMessage
+	case u.EditedMessage != nil:
+		return UpdateTypeEditedMessage
+	case u.ChannelPost != nil:
+		return UpdateTypeChannelPost
+	case u.EditedChannelPost != nil:
+		return UpdateTypeEditedChannelPost
+	case u.InlineQuery != nil:
+		return UpdateTypeInlineQuery
+	case u.ChosenInlineResult != nil:
+		return UpdateTypeChosenInlineResult
+	case u.CallbackQuery != nil:
+		return UpdateTypeCallbackQuery
+	case u.ShippingQuery != nil:
+		return UpdateTypeShippingQuery
+	case u.PreCheckoutQuery != nil:
+		return UpdateTypePreCheckoutQuery
+	case u.Poll != nil:
+		return UpdateTypePoll
+	case u.PollAnswer != nil:
+		return UpdateTypePollAnswer
+	}
+
+	return ""
+}
+
+// effectiveMessage returns the message the update carries, whether it is a new or edited message or channel post.
+func (u *Update) effectiveMessage() *Message {
+	switch {
+	case u.Message != nil:
+		return u.Message
+	case u.EditedMessage != nil:
+		return u.EditedMessage
+	case u.ChannelPost != nil:
+		return u.ChannelPost
+	case u.EditedChannelPost != nil:
+		return u.EditedChannelPost
+	}
+
+	return nil
+}
+
+// Chat returns the chat the update belongs to, or nil if it isn't associated with a chat.
+func (u *Update) Chat() *Chat {
+	if message := u.effectiveMessage(); message != nil {
+		return message.Chat
+	}
+
+	if u.CallbackQuery != nil && u.CallbackQuery.Message != nil {
+		return u.CallbackQuery.Message.Chat
+	}
+
+	return nil
+}
+
+// Sender returns the user that triggered the update, or nil if it wasn't triggered by a user, as for channel posts.
+func (u *Update) Sender() *User {
+	switch {
+	case u.Message != nil:
+		return u.Message.Sender
+	case u.EditedMessage != nil:
+		return u.EditedMessage.Sender
+	case u.InlineQuery != nil:
+		return u.InlineQuery.Sender
+	case u.ChosenInlineResult != nil:
+		return u.ChosenInlineResult.From
+	case u.CallbackQuery != nil:
+		return u.CallbackQuery.From
+	case u.ShippingQuery != nil:
+		return u.ShippingQuery.From
+	case u.PreCheckoutQuery != nil:
+		return u.PreCheckoutQuery.From
+	case u.PollAnswer != nil:
+		return u.PollAnswer.User
+	}
+
+	return nil
+}
+
 // See https://core.telegram.org/bots/api#getupdates
 type getUpdatesRequest struct {
 	Offset         int      `json:"offset"`
@@ -79,8 +179,3 @@ type getUpdatesRequest struct {
 	Timeout        int      `json:"timeout"`
 	AllowedUpdates []string `json:"allowed_updates"`
 }
-
-type getUpdatesResponse struct {
-	Ok     bool      `json:"ok"`
-	Result []*Update `json:"result"`
-}
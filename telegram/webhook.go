@@ -1,21 +1,39 @@
 package telegram // import "heytobi.dev/fuse/telegram"
 
 // Webhook defines an endpoint for receiving telegram updates.
+//
+// Certificate is the public key certificate of the endpoint, it's only required for self-signed certificates and has
+// to be uploaded, i.e. read from a path or a reader. SecretToken is sent by Telegram in the
+// X-Telegram-Bot-Api-Secret-Token header of every webhook request, so that the bot only accepts requests from Telegram.
 // See https://core.telegram.org/bots/api#setwebhook
 type Webhook struct {
-	Url                string   `json:"url"`
-	IPAddress          string   `json:"ip_address"`
-	MaxConnections     int      `json:"max_connections"`
-	AllowedUpdates     []string `json:"allowed_updates"`
-	DropPendingUpdates bool     `json:"drop_pending_updates"`
+	Url                string     `json:"url"`
+	Certificate        *InputFile `json:"certificate,omitempty"`
+	IPAddress          string     `json:"ip_address"`
+	MaxConnections     int        `json:"max_connections"`
+	AllowedUpdates     []string   `json:"allowed_updates"`
+	DropPendingUpdates bool       `json:"drop_pending_updates"`
+	SecretToken        string     `json:"secret_token,omitempty"`
+}
+
+func (w *Webhook) files() map[string]*InputFile {
+	return map[string]*InputFile{"certificate": w.Certificate}
 }
 
 type deleteWebhookRequest struct {
 	DropPendingUpdates bool `json:"drop_pending_updates"`
 }
 
-type webhookResponse struct {
-	Ok          bool   `json:"ok"`
-	Result      bool   `json:"result"`
-	Description string `json:"description"`
+// WebhookInfo describes the current status of the webhook of the bot.
+// See https://core.telegram.org/bots/api#webhookinfo
+type WebhookInfo struct {
+	Url                          string   `json:"url"`
+	HasCustomCertificate         bool     `json:"has_custom_certificate"`
+	PendingUpdateCount           int      `json:"pending_update_count"`
+	IPAddress                    string   `json:"ip_address"`
+	LastErrorDate                int      `json:"last_error_date"`
+	LastErrorMessage             string   `json:"last_error_message"`
+	LastSynchronizationErrorDate int      `json:"last_synchronization_error_date"`
+	MaxConnections               int      `json:"max_connections"`
+	AllowedUpdates               []string `json:"allowed_updates"`
 }
@@ -0,0 +1,164 @@
+package telegram // import "heytobi.dev/fuse/telegram"
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	webhookSecretTokenHeader = "X-Telegram-Bot-Api-Secret-Token"
+	webhookSecretTokenLabel  = "webhook secret token"
+
+	defaultWebhookServerAddress = ":8443"
+	defaultWebhookServerPath    = "/"
+
+	maxWebhookRequestBodyBytes = 1 << 20
+	webhookReadHeaderTimeout   = 10 * time.Second
+)
+
+// WebhookServerConfig defines the parameters of the http server receiving webhook updates.
+// Telegram only delivers webhook updates over HTTPS on ports 443, 80, 88 or 8443. If CertFile and KeyFile are set, the
+// server terminates TLS itself, otherwise it expects to run behind a proxy that does.
+type WebhookServerConfig struct {
+	Address  string
+	Path     string
+	CertFile string
+	KeyFile  string
+}
+
+// WebhookServer is an http server that receives updates pushed by Telegram and relays them to a Bot.
+// See https://core.telegram.org/bots/api#setwebhook
+type WebhookServer struct {
+	config *WebhookServerConfig
+	server *http.Server
+}
+
+// NewWebhookServer initializes a WebhookServer that relays received updates to the given bot.
+//
+// If no Address is specified in the config, it defaults to :8443. If no Path is specified, updates are accepted on /.
+//
+// It returns an error if any of these conditions are met:
+//   - The given bot is nil
+//   - The given config is nil
+//   - Only one of CertFile and KeyFile is configured.
+func NewWebhookServer(bot *Bot, config *WebhookServerConfig) (*WebhookServer, error) {
+	if bot == nil {
+		return nil, errNilBot
+	}
+
+	if config == nil {
+		return nil, errNilWebhookServerConfig
+	}
+
+	if (config.CertFile == "") != (config.KeyFile == "") {
+		return nil, errIncompleteTLSConfig
+	}
+
+	if config.Address == "" {
+		config.Address = defaultWebhookServerAddress
+	}
+
+	if config.Path == "" {
+		config.Path = defaultWebhookServerPath
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle(config.Path, bot)
+
+	return &WebhookServer{
+		config: config,
+		server: &http.Server{
+			Addr:              config.Address,
+			Handler:           mux,
+			ReadHeaderTimeout: webhookReadHeaderTimeout,
+		},
+	}, nil
+}
+
+// ListenAndServe starts accepting webhook requests, it blocks until the server is shut down.
+// It returns nil if the server was stopped through Shutdown.
+func (s *WebhookServer) ListenAndServe() error {
+	var err error
+	if s.config.CertFile != "" {
+		err = s.server.ListenAndServeTLS(s.config.CertFile, s.config.KeyFile)
+	} else {
+		err = s.server.ListenAndServe()
+	}
+
+	if err == http.ErrServerClosed {
+		return nil
+	}
+
+	return err
+}
+
+// Shutdown gracefully stops the server, waiting for in-flight requests to complete until the given context is done.
+func (s *WebhookServer) Shutdown(ctx context.Context) error {
+	return s.server.Shutdown(ctx)
+}
+
+// ServeHTTP implements http.Handler, allowing the bot to receive updates pushed by Telegram to a webhook.
+// Received updates are acknowledged as soon as they are queued and are then processed asynchronously by the handlers
+// registered on the bot, the bot therefore has to be started for updates to be accepted.
+// If a WebhookSecretToken is configured, or once a webhook has been registered with a secret token, requests without a
+// matching X-Telegram-Bot-Api-Secret-Token header are rejected.
+func (b *Bot) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	if !b.isValidSecretToken(r.Header.Get(webhookSecretTokenHeader)) {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	var update Update
+	err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxWebhookRequestBodyBytes)).Decode(&update)
+	if err != nil {
+		logrus.WithError(err).Error("failed to decode webhook update")
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	if !b.isRunning {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+
+	select {
+	case b.updatesChan <- &update:
+		w.WriteHeader(http.StatusOK)
+	case <-r.Context().Done():
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+}
+
+func (b *Bot) isValidSecretToken(token string) bool {
+	expectedToken := b.webhookSecretToken.Load()
+	if expectedToken == nil || *expectedToken == "" {
+		return true
+	}
+
+	return subtle.ConstantTimeCompare([]byte(token), []byte(*expectedToken)) == 1
+}
+
+// deriveSecretToken returns the webhook secret token derived from the given bot token, made of characters allowed by
+// Telegram. The bot token is secret, so is the derived token, while staying the same across restarts and instances.
+func deriveSecretToken(botToken string) string {
+	mac := hmac.New(sha256.New, []byte(botToken))
+	mac.Write([]byte(webhookSecretTokenLabel))
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,243 @@
+package telegram
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewWebhookServer_ReturnErrorIfBotIsNil(t *testing.T) {
+	server, err := NewWebhookServer(nil, &WebhookServerConfig{})
+
+	assert.Nil(t, server)
+	assert.Equal(t, errNilBot, err)
+}
+
+func TestNewWebhookServer_ReturnErrorIfConfigIsNil(t *testing.T) {
+	bot, _ := NewBot(&Config{Token: "test", UpdateMethod: UpdateMethodWebhook}, &mockHttpClient{})
+	server, err := NewWebhookServer(bot, nil)
+
+	assert.Nil(t, server)
+	assert.Equal(t, errNilWebhookServerConfig, err)
+}
+
+func TestNewWebhookServer_ReturnErrorIfTLSConfigIsIncomplete(t *testing.T) {
+	bot, _ := NewBot(&Config{Token: "test", UpdateMethod: UpdateMethodWebhook}, &mockHttpClient{})
+	server, err := NewWebhookServer(bot, &WebhookServerConfig{CertFile: "cert.pem"})
+
+	assert.Nil(t, server)
+	assert.Equal(t, errIncompleteTLSConfig, err)
+}
+
+func TestNewWebhookServer_InitializeWithDefaults(t *testing.T) {
+	bot, _ := NewBot(&Config{Token: "test", UpdateMethod: UpdateMethodWebhook}, &mockHttpClient{})
+	server, err := NewWebhookServer(bot, &WebhookServerConfig{})
+
+	assert.NoError(t, err)
+	assert.Equal(t, defaultWebhookServerAddress, server.config.Address)
+	assert.Equal(t, defaultWebhookServerPath, server.config.Path)
+}
+
+func TestServeHTTP_RejectNonPostRequests(t *testing.T) {
+	bot, _ := NewBot(&Config{Token: "test", UpdateMethod: UpdateMethodWebhook}, &mockHttpClient{})
+	_ = bot.Start()
+
+	recorder := httptest.NewRecorder()
+	bot.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.Equal(t, http.StatusMethodNotAllowed, recorder.Code)
+}
+
+func TestServeHTTP_RejectRequestsWithInvalidSecretToken(t *testing.T) {
+	bot, _ := NewBot(
+		&Config{Token: "test", UpdateMethod: UpdateMethodWebhook, WebhookSecretToken: "secret"},
+		&mockHttpClient{},
+	)
+	_ = bot.Start()
+
+	request := httptest.NewRequest(http.MethodPost, "/", bytes.NewBufferString(`{"update_id": 1}`))
+	request.Header.Set(webhookSecretTokenHeader, "wrong")
+
+	recorder := httptest.NewRecorder()
+	bot.ServeHTTP(recorder, request)
+
+	assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+}
+
+func TestServeHTTP_RejectInvalidUpdates(t *testing.T) {
+	bot, _ := NewBot(&Config{Token: "test", UpdateMethod: UpdateMethodWebhook}, &mockHttpClient{})
+	_ = bot.Start()
+
+	recorder := httptest.NewRecorder()
+	bot.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/", bytes.NewBufferString(`invalid json`)))
+
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+}
+
+func TestServeHTTP_RejectUpdatesIfBotIsNotRunning(t *testing.T) {
+	bot, _ := NewBot(&Config{Token: "test", UpdateMethod: UpdateMethodWebhook}, &mockHttpClient{})
+
+	recorder := httptest.NewRecorder()
+	bot.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/", bytes.NewBufferString(`{"update_id": 1}`)))
+
+	assert.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+}
+
+func TestServeHTTP_DispatchUpdatesToHandlers(t *testing.T) {
+	bot, _ := NewBot(
+		&Config{Token: "test", UpdateMethod: UpdateMethodWebhook, WebhookSecretToken: "secret"},
+		&mockHttpClient{},
+	)
+
+	handled := make(chan *Update, 1)
+	_ = bot.RegisterHandler("/start", func(ctx context.Context, update *Update) error {
+		handled <- update
+		return nil
+	})
+	_ = bot.Start()
+
+	body := `{"update_id": 7, "message": {"text": "/start"}}`
+	request := httptest.NewRequest(http.MethodPost, "/", bytes.NewBufferString(body))
+	request.Header.Set(webhookSecretTokenHeader, "secret")
+
+	recorder := httptest.NewRecorder()
+	bot.ServeHTTP(recorder, request)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+
+	select {
+	case update := <-handled:
+		assert.Equal(t, 7, update.ID)
+	case <-time.After(time.Second):
+		t.Fatal("update was not dispatched to the handler")
+	}
+}
+
+func TestServeHTTP_VerifySecretTokenDerivedWhenRegisteringWebhook(t *testing.T) {
+	httpClient, requests := newRecordingHttpClient(`{"ok": true, "result": true}`)
+	bot, _ := NewBot(&Config{Token: "test", UpdateMethod: UpdateMethodWebhook}, httpClient)
+	_, _ = bot.RegisterWebhook(context.Background(), &Webhook{Url: "https://example.com/hook"})
+	_ = bot.Start()
+	defer func() { _ = bot.Stop(context.Background()) }()
+
+	var webhook Webhook
+	_ = json.Unmarshal([]byte(requests[endpointSetWebhook][0]), &webhook)
+	assert.Regexp(t, "^[0-9a-f]{64}$", webhook.SecretToken)
+	assert.Equal(t, deriveSecretToken("test"), webhook.SecretToken)
+
+	for token, expectedCode := range map[string]int{"": http.StatusUnauthorized, webhook.SecretToken: http.StatusOK} {
+		request := httptest.NewRequest(http.MethodPost, "/", bytes.NewBufferString(`{"update_id": 1}`))
+		request.Header.Set(webhookSecretTokenHeader, token)
+
+		recorder := httptest.NewRecorder()
+		bot.ServeHTTP(recorder, request)
+
+		assert.Equal(t, expectedCode, recorder.Code)
+	}
+}
+
+func TestRegisterWebhook_UseConfiguredSecretToken(t *testing.T) {
+	httpClient, requests := newRecordingHttpClient(`{"ok": true, "result": true}`)
+	bot, _ := NewBot(
+		&Config{Token: "test", UpdateMethod: UpdateMethodWebhook, WebhookSecretToken: "secret"},
+		httpClient,
+	)
+
+	webhook := &Webhook{Url: "https://example.com/hook"}
+	_, err := bot.RegisterWebhook(context.Background(), webhook)
+
+	assert.NoError(t, err)
+	assert.Contains(t, requests[endpointSetWebhook][0], `"secret_token":"secret"`)
+	assert.Empty(t, webhook.SecretToken)
+}
+
+func TestRegisterWebhook_UploadCertificateAsMultipart(t *testing.T) {
+	var form *multipart.Form
+	httpClient := httpClientFunc(func(request *http.Request) (*http.Response, error) {
+		form = readMultipartForm(t, request)
+		return newResponse(http.StatusOK, `{"ok": true, "result": true}`), nil
+	})
+	bot, _ := NewBot(&Config{Token: "test", UpdateMethod: UpdateMethodWebhook}, httpClient)
+
+	registered, err := bot.RegisterWebhook(context.Background(), &Webhook{
+		Url:         "https://example.com/hook",
+		Certificate: FileFromReader("cert.pem", strings.NewReader("certificate")),
+		SecretToken: "secret",
+	})
+
+	assert.NoError(t, err)
+	assert.True(t, registered)
+	assert.Equal(t, "https://example.com/hook", form.Value["url"][0])
+	assert.Equal(t, "secret", form.Value["secret_token"][0])
+	assert.Equal(t, "certificate", readFormFile(t, form.File["certificate"][0]))
+}
+
+func TestStart_DontRegisterUnchangedWebhookWithDerivedSecretToken(t *testing.T) {
+	httpClient := &mockHttpClient{}
+	httpClient.On("Do", requestTo(endpointGetWebhookInfo)).Return(newResponse(http.StatusOK,
+		`{"ok": true, "result": {"url": "https://example.com/hook"}}`), nil).Once()
+	bot, _ := NewBot(&Config{Token: "test", UpdateMethod: UpdateMethodWebhook}, httpClient)
+	bot.WithWebhook(&Webhook{Url: "https://example.com/hook"})
+
+	err := bot.Start()
+	defer func() { _ = bot.Stop(context.Background()) }()
+
+	assert.NoError(t, err)
+	httpClient.AssertNotCalled(t, "Do", requestTo(endpointSetWebhook))
+	assert.True(t, bot.isValidSecretToken(deriveSecretToken("test")))
+	assert.False(t, bot.isValidSecretToken(""))
+}
+
+func TestDeriveSecretToken_ReturnSameTokenForSameBotToken(t *testing.T) {
+	assert.Equal(t, deriveSecretToken("test"), deriveSecretToken("test"))
+	assert.NotEqual(t, deriveSecretToken("test"), deriveSecretToken("other"))
+	assert.NotContains(t, deriveSecretToken("test"), "test")
+}
+
+func TestStart_DontRegisterUnchangedWebhookWithConfiguredSecretToken(t *testing.T) {
+	httpClient := &mockHttpClient{}
+	httpClient.On("Do", requestTo(endpointGetWebhookInfo)).Return(newResponse(http.StatusOK,
+		`{"ok": true, "result": {"url": "https://example.com/hook"}}`), nil).Once()
+	bot, _ := NewBot(
+		&Config{Token: "test", UpdateMethod: UpdateMethodWebhook, WebhookSecretToken: "secret"},
+		httpClient,
+	)
+	bot.WithWebhook(&Webhook{Url: "https://example.com/hook"})
+
+	err := bot.Start()
+	defer func() { _ = bot.Stop(context.Background()) }()
+
+	assert.NoError(t, err)
+	httpClient.AssertNotCalled(t, "Do", requestTo(endpointSetWebhook))
+	assert.True(t, bot.isValidSecretToken("secret"))
+	assert.False(t, bot.isValidSecretToken(""))
+}
+
+func TestStart_RegisterWebhookWithChangedSecretToken(t *testing.T) {
+	httpClient := &mockHttpClient{}
+	httpClient.On("Do", requestTo(endpointGetWebhookInfo)).Return(newResponse(http.StatusOK,
+		`{"ok": true, "result": {"url": "https://example.com/hook", "last_error_date": 1700000000, `+
+			`"last_error_message": "Wrong response from the webhook: 401 Unauthorized"}}`), nil).Once()
+	httpClient.On("Do", requestTo(endpointSetWebhook)).Return(newResponse(http.StatusOK,
+		`{"ok": true, "result": true}`), nil).Once()
+	bot, _ := NewBot(&Config{Token: "test", UpdateMethod: UpdateMethodWebhook}, httpClient)
+	bot.WithWebhook(&Webhook{Url: "https://example.com/hook", SecretToken: "secret"})
+
+	err := bot.Start()
+	defer func() { _ = bot.Stop(context.Background()) }()
+
+	assert.NoError(t, err)
+	httpClient.AssertExpectations(t)
+	body, _ := io.ReadAll(httpClient.Calls[1].Arguments.Get(0).(*http.Request).Body)
+	assert.Contains(t, string(body), `"secret_token":"secret"`)
+	assert.True(t, bot.isValidSecretToken("secret"))
+}
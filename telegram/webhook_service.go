@@ -1,138 +1,127 @@
 package telegram // import "heytobi.dev/fuse/telegram"
 
 import (
-	"bytes"
-	"encoding/json"
-	"fmt"
-	"io"
-	"net/http"
-
-	"github.com/pkg/errors"
-	"github.com/sirupsen/logrus"
+	"context"
+	"slices"
+	"strings"
 )
 
+const webhookUnauthorizedError = "401 Unauthorized"
+
 type webhookService struct {
-	httpClient     httpClient
-	apiUrlFmt      string
-	token          string
+	executor       *executor
 	AllowedUpdates []string `json:"allowed_updates"`
 }
 
-func newWebhookService(httpClient httpClient, apirUrlFmt, token string, allowedUpdates []string) (*webhookService, error) {
+func newWebhookService(executor *executor, allowedUpdates []string) (*webhookService, error) {
 	return &webhookService{
-		httpClient:     httpClient,
-		apiUrlFmt:      apirUrlFmt,
-		token:          token,
+		executor:       executor,
 		AllowedUpdates: allowedUpdates,
 	}, nil
 }
 
-func (s *webhookService) registerWebhook(webhook *Webhook) (bool, error) {
+// registerWebhook registers the given webhook, it receives the updates allowed by the service unless it specifies
+// which updates it allows, and it's registered with the secret token derived from the bot token unless it has one.
+// See https://core.telegram.org/bots/api#setwebhook
+func (s *webhookService) registerWebhook(ctx context.Context, webhook *Webhook) (bool, error) {
 	if webhook.Url == "" {
 		return false, errMissingWebhookUrl
 	}
 
-	url := fmt.Sprintf(s.apiUrlFmt, s.token, endpointSetWebhook)
-
 	if webhook.AllowedUpdates == nil {
 		webhook.AllowedUpdates = s.AllowedUpdates
 	}
+	webhook.SecretToken = s.secretToken(webhook)
 
-	bodyJson, err := json.Marshal(webhook)
+	var registered bool
+	err := s.executor.execute(ctx, endpointSetWebhook, webhook, &registered)
 	if err != nil {
-		return false, errors.Wrap(err, "failed to marshal register webhook request body")
+		return false, err
 	}
 
-	request, err := http.NewRequest(httpPost, url, bytes.NewBuffer(bodyJson))
-	if err != nil {
-		return false, errors.Wrap(err, "failed to create register webhook request")
-	}
-	request.Header.Set("Content-Type", "application/json")
+	return registered, nil
+}
 
-	response, err := s.httpClient.Do(request)
+// getWebhookInfo gets the status of the registered webhook, its url is empty if no webhook is registered.
+// See https://core.telegram.org/bots/api#getwebhookinfo
+func (s *webhookService) getWebhookInfo(ctx context.Context) (*WebhookInfo, error) {
+	var info WebhookInfo
+	err := s.executor.execute(ctx, endpointGetWebhookInfo, struct{}{}, &info)
 	if err != nil {
-		return false, errors.Wrap(err, "register webhook http request failed")
+		return nil, err
 	}
-	defer func(body io.ReadCloser) {
-		err := body.Close()
-		if err != nil {
-			logrus.WithError(err).Error("failed to close response body")
-		}
-	}(response.Body)
-
-	if response.StatusCode != http.StatusOK {
-		return false, errors.New(
-			fmt.Sprintf(
-				"failed to register webhook, unexpected response code: %d, %s",
-				response.StatusCode,
-				response.Body,
-			),
-		)
+
+	return &info, nil
+}
+
+// syncWebhook registers the given webhook unless it's already registered with the same url, allowed updates and, if
+// it specifies them, ip address and maximum number of connections. Since Telegram doesn't report the certificate of the
+// registered webhook, the webhook is always registered if it has a certificate, and it's registered if the registered
+// webhook has a certificate but the given one doesn't. Telegram doesn't report the secret token either, so the webhook
+// is also registered if Telegram reports that the last update was rejected as unauthorized, e.g. since the webhook was
+// registered with another secret token. It returns whether the webhook was registered.
+func (s *webhookService) syncWebhook(ctx context.Context, webhook *Webhook) (bool, error) {
+	if webhook.Url == "" {
+		return false, errMissingWebhookUrl
 	}
 
-	responseBody, err := io.ReadAll(response.Body)
-	if err != nil {
-		return false, errors.Wrap(err, "failed to parse register webhook response body")
+	if webhook.Certificate != nil {
+		return s.registerWebhook(ctx, webhook)
 	}
 
-	var resp webhookResponse
-	err = json.Unmarshal(responseBody, &resp)
+	info, err := s.getWebhookInfo(ctx)
 	if err != nil {
-		return false, errors.Wrap(err, "failed to unmarshall setWebhook response")
+		return false, err
 	}
 
-	return resp.Ok, nil
-}
+	allowedUpdates := webhook.AllowedUpdates
+	if allowedUpdates == nil {
+		allowedUpdates = s.AllowedUpdates
+	}
 
-// deleteWebhook deletes the registered webhook.
-// See https://core.telegram.org/bots/api#deletewebhook
-func (s *webhookService) deleteWebhook(dropPendingUpdates bool) (bool, error) {
-	url := fmt.Sprintf(s.apiUrlFmt, s.token, endpointDeleteWebhook)
+	if info.Url == webhook.Url &&
+		!info.HasCustomCertificate &&
+		!strings.Contains(info.LastErrorMessage, webhookUnauthorizedError) &&
+		sameUpdateTypes(info.AllowedUpdates, allowedUpdates) &&
+		(webhook.IPAddress == "" || info.IPAddress == webhook.IPAddress) &&
+		(webhook.MaxConnections == 0 || info.MaxConnections == webhook.MaxConnections) {
+		return false, nil
+	}
 
-	body := deleteWebhookRequest{DropPendingUpdates: dropPendingUpdates}
+	return s.registerWebhook(ctx, webhook)
+}
 
-	bodyJson, err := json.Marshal(body)
-	if err != nil {
-		return false, errors.Wrap(err, "failed to marshal delete webhook request body")
+// secretToken returns the secret token of the given webhook, or the one derived from the bot token if it has none.
+func (s *webhookService) secretToken(webhook *Webhook) string {
+	if webhook.SecretToken != "" {
+		return webhook.SecretToken
 	}
 
-	request, err := http.NewRequest(httpPost, url, bytes.NewBuffer(bodyJson))
-	if err != nil {
-		return false, errors.Wrap(err, "failed to create delete webhook request")
-	}
-	request.Header.Set("Content-Type", "application/json")
+	return deriveSecretToken(s.executor.token)
+}
 
-	response, err := s.httpClient.Do(request)
+// deleteWebhook deletes the registered webhook.
+// See https://core.telegram.org/bots/api#deletewebhook
+func (s *webhookService) deleteWebhook(ctx context.Context, dropPendingUpdates bool) (bool, error) {
+	var deleted bool
+	err := s.executor.execute(
+		ctx,
+		endpointDeleteWebhook,
+		deleteWebhookRequest{DropPendingUpdates: dropPendingUpdates},
+		&deleted,
+	)
 	if err != nil {
-		return false, errors.Wrap(err, "delete webhook http request failed")
-	}
-	defer func(body io.ReadCloser) {
-		err := body.Close()
-		if err != nil {
-			logrus.WithError(err).Error("failed to close response body")
-		}
-	}(response.Body)
-
-	if response.StatusCode != http.StatusOK {
-		return false, errors.New(
-			fmt.Sprintf(
-				"failed to delete webhook, unexpected response code: %d, %s",
-				response.StatusCode,
-				response.Body,
-			),
-		)
+		return false, err
 	}
 
-	responseBody, err := io.ReadAll(response.Body)
-	if err != nil {
-		return false, errors.Wrap(err, "failed to parse delete webhook response body")
-	}
+	return deleted, nil
+}
 
-	var resp webhookResponse
-	err = json.Unmarshal(responseBody, &resp)
-	if err != nil {
-		return false, errors.Wrap(err, "failed to unmarshall delete Webhook response")
-	}
+// sameUpdateTypes checks if the given lists contain the same update types, regardless of their order.
+func sameUpdateTypes(a []string, b []string) bool {
+	a, b = slices.Clone(a), slices.Clone(b)
+	slices.Sort(a)
+	slices.Sort(b)
 
-	return resp.Ok, nil
+	return slices.Equal(slices.Compact(a), slices.Compact(b))
 }
@@ -2,6 +2,7 @@ package telegram
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"io"
@@ -15,7 +16,7 @@ import (
 
 func TestDeleteWebhook_CanDeleteWebhook(t *testing.T) {
 	httpClient := &mockHttpClient{}
-	response := webhookResponse{Ok: true}
+	response := apiResponse{Ok: true, Result: json.RawMessage(`true`)}
 	responseJson, _ := json.Marshal(response)
 	responseBody := io.NopCloser(bytes.NewBufferString(string(responseJson)))
 	httpClient.On("Do", mock.Anything, mock.Anything).Return(&http.Response{
@@ -24,9 +25,9 @@ func TestDeleteWebhook_CanDeleteWebhook(t *testing.T) {
 	}, nil)
 
 	var allowedUpdates []string
-	service, _ := newWebhookService(httpClient, testApiUrlFmt, testToken, allowedUpdates)
+	service, _ := newWebhookService(newExecutor(httpClient, testApiUrlFmt, testToken, nil), allowedUpdates)
 
-	success, err := service.deleteWebhook(true)
+	success, err := service.deleteWebhook(context.Background(), true)
 
 	assert.True(t, success)
 	assert.Nil(t, err)
@@ -34,7 +35,7 @@ func TestDeleteWebhook_CanDeleteWebhook(t *testing.T) {
 
 func TestDeleteWebhook_ReturnsFalseIfResponseCodeIsNot200(t *testing.T) {
 	httpClient := &mockHttpClient{}
-	response := webhookResponse{Ok: false}
+	response := apiResponse{Ok: false, ErrorCode: 500, Description: "Internal Server Error"}
 	responseJson, _ := json.Marshal(response)
 	responseBody := io.NopCloser(bytes.NewBufferString(string(responseJson)))
 	httpClient.On("Do", mock.Anything, mock.Anything).Return(&http.Response{
@@ -43,13 +44,15 @@ func TestDeleteWebhook_ReturnsFalseIfResponseCodeIsNot200(t *testing.T) {
 	}, nil)
 
 	var allowedUpdates []string
-	service, _ := newWebhookService(httpClient, testApiUrlFmt, testToken, allowedUpdates)
+	service, _ := newWebhookService(newExecutor(httpClient, testApiUrlFmt, testToken, nil), allowedUpdates)
 
-	success, err := service.deleteWebhook(true)
+	success, err := service.deleteWebhook(context.Background(), true)
 
 	assert.False(t, success)
 	assert.Error(t, err)
-	assert.True(t, strings.HasPrefix(err.Error(), "failed to delete webhook, unexpected response code"))
+	var apiErr *APIError
+	assert.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, 500, apiErr.ErrorCode)
 }
 
 func TestDeleteWebhook_ReturnsFalseIfDeleteWebhookRequestFails(t *testing.T) {
@@ -57,11 +60,148 @@ func TestDeleteWebhook_ReturnsFalseIfDeleteWebhookRequestFails(t *testing.T) {
 	httpClient.On("Do", mock.Anything, mock.Anything).Return(nil, errors.New("error"))
 
 	var allowedUpdates []string
-	service, _ := newWebhookService(httpClient, testApiUrlFmt, testToken, allowedUpdates)
+	service, _ := newWebhookService(newExecutor(httpClient, testApiUrlFmt, testToken, nil), allowedUpdates)
 
-	success, err := service.deleteWebhook(true)
+	success, err := service.deleteWebhook(context.Background(), true)
 
 	assert.False(t, success)
 	assert.Error(t, err)
-	assert.True(t, strings.EqualFold(err.Error(), "delete webhook http request failed: error"))
+	assert.True(t, strings.EqualFold(err.Error(), "deleteWebhook request failed: error"))
+}
+
+func TestGetWebhookInfo_ReturnWebhookInfo(t *testing.T) {
+	httpClient, _ := newRecordingHttpClient(`{"ok": true, "result": {"url": "https://example.com/hook", ` +
+		`"pending_update_count": 3, "ip_address": "1.2.3.4", "last_error_date": 1700000000, ` +
+		`"last_error_message": "Connection refused", "max_connections": 40, "allowed_updates": ["message"]}}`)
+	service, _ := newWebhookService(newExecutor(httpClient, testApiUrlFmt, testToken, nil), nil)
+
+	info, err := service.getWebhookInfo(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, &WebhookInfo{
+		Url:                "https://example.com/hook",
+		PendingUpdateCount: 3,
+		IPAddress:          "1.2.3.4",
+		LastErrorDate:      1700000000,
+		LastErrorMessage:   "Connection refused",
+		MaxConnections:     40,
+		AllowedUpdates:     []string{"message"},
+	}, info)
+}
+
+func TestSyncWebhook_DontRegisterWebhookIfUnchanged(t *testing.T) {
+	httpClient := &mockHttpClient{}
+	httpClient.On("Do", requestTo(endpointGetWebhookInfo)).Return(newResponse(http.StatusOK,
+		`{"ok": true, "result": {"url": "https://example.com/hook", "max_connections": 40, `+
+			`"allowed_updates": ["callback_query", "message"]}}`), nil)
+	allowedUpdates := []string{UpdateTypeMessage, UpdateTypeCallbackQuery}
+	service, _ := newWebhookService(newExecutor(httpClient, testApiUrlFmt, testToken, nil), allowedUpdates)
+
+	registered, err := service.syncWebhook(context.Background(), &Webhook{Url: "https://example.com/hook"})
+
+	assert.NoError(t, err)
+	assert.False(t, registered)
+	httpClient.AssertNotCalled(t, "Do", requestTo(endpointSetWebhook))
+}
+
+func TestSyncWebhook_RegisterWebhookIfChanged(t *testing.T) {
+	webhooks := []*Webhook{
+		{Url: "https://example.com/new-hook"},
+		{Url: "https://example.com/hook", AllowedUpdates: []string{UpdateTypeMessage}},
+		{Url: "https://example.com/hook", MaxConnections: 100},
+		{Url: "https://example.com/hook", IPAddress: "5.6.7.8"},
+		{Url: "https://example.com/hook", Certificate: FileFromReader("cert.pem", strings.NewReader("certificate"))},
+	}
+
+	for _, webhook := range webhooks {
+		httpClient := &mockHttpClient{}
+		httpClient.On("Do", requestTo(endpointGetWebhookInfo)).Return(newResponse(http.StatusOK,
+			`{"ok": true, "result": {"url": "https://example.com/hook", "max_connections": 40, `+
+				`"ip_address": "1.2.3.4"}}`), nil).Maybe()
+		httpClient.On("Do", requestTo(endpointSetWebhook)).Return(newResponse(http.StatusOK,
+			`{"ok": true, "result": true}`), nil).Once()
+		service, _ := newWebhookService(newExecutor(httpClient, testApiUrlFmt, testToken, nil), nil)
+
+		registered, err := service.syncWebhook(context.Background(), webhook)
+
+		assert.NoError(t, err)
+		assert.True(t, registered)
+		httpClient.AssertExpectations(t)
+	}
+}
+
+func TestSyncWebhook_RegisterWebhookToRemoveCertificate(t *testing.T) {
+	httpClient := &mockHttpClient{}
+	httpClient.On("Do", requestTo(endpointGetWebhookInfo)).Return(newResponse(http.StatusOK,
+		`{"ok": true, "result": {"url": "https://example.com/hook", "has_custom_certificate": true}}`), nil).Once()
+	httpClient.On("Do", requestTo(endpointSetWebhook)).Return(newResponse(http.StatusOK,
+		`{"ok": true, "result": true}`), nil).Once()
+	service, _ := newWebhookService(newExecutor(httpClient, testApiUrlFmt, testToken, nil), nil)
+
+	registered, err := service.syncWebhook(context.Background(), &Webhook{Url: "https://example.com/hook"})
+
+	assert.NoError(t, err)
+	assert.True(t, registered)
+	httpClient.AssertExpectations(t)
+}
+
+func TestSyncWebhook_RegisterWebhookIfUpdatesAreRejectedAsUnauthorized(t *testing.T) {
+	httpClient := &mockHttpClient{}
+	httpClient.On("Do", requestTo(endpointGetWebhookInfo)).Return(newResponse(http.StatusOK,
+		`{"ok": true, "result": {"url": "https://example.com/hook", "last_error_date": 1700000000, `+
+			`"last_error_message": "Wrong response from the webhook: 401 Unauthorized"}}`), nil).Once()
+	httpClient.On("Do", requestTo(endpointSetWebhook)).Return(newResponse(http.StatusOK,
+		`{"ok": true, "result": true}`), nil).Once()
+	service, _ := newWebhookService(newExecutor(httpClient, testApiUrlFmt, testToken, nil), nil)
+
+	registered, err := service.syncWebhook(context.Background(), &Webhook{Url: "https://example.com/hook"})
+
+	assert.NoError(t, err)
+	assert.True(t, registered)
+	httpClient.AssertExpectations(t)
+	body, _ := io.ReadAll(httpClient.Calls[1].Arguments.Get(0).(*http.Request).Body)
+	assert.Contains(t, string(body), `"secret_token":"`+deriveSecretToken(testToken)+`"`)
+}
+
+func TestStart_SyncWebhook(t *testing.T) {
+	httpClient := &mockHttpClient{}
+	httpClient.On("Do", requestTo(endpointGetWebhookInfo)).Return(newResponse(http.StatusOK,
+		`{"ok": true, "result": {"url": ""}}`), nil).Once()
+	httpClient.On("Do", requestTo(endpointSetWebhook)).Return(newResponse(http.StatusOK,
+		`{"ok": true, "result": true}`), nil).Once()
+	bot, _ := NewBot(&Config{Token: "test", UpdateMethod: UpdateMethodWebhook}, httpClient)
+	bot.WithWebhook(&Webhook{Url: "https://example.com/hook"})
+
+	err := bot.Start()
+	defer func() { _ = bot.Stop(context.Background()) }()
+
+	assert.NoError(t, err)
+	httpClient.AssertExpectations(t)
+	body, _ := io.ReadAll(httpClient.Calls[1].Arguments.Get(0).(*http.Request).Body)
+	assert.Contains(t, string(body), `"url":"https://example.com/hook"`)
+}
+
+func TestLogOut_LogOutSuccessfully(t *testing.T) {
+	httpClient, requests := newRecordingHttpClient(`{"ok": true, "result": true}`)
+	service, _ := newAccountService(newExecutor(httpClient, testApiUrlFmt, testToken, nil))
+
+	loggedOut, err := service.logOut(context.Background())
+
+	assert.NoError(t, err)
+	assert.True(t, loggedOut)
+	assert.Len(t, requests[endpointLogOut], 1)
+}
+
+func TestClose_ReturnErrorIfClosedTooEarly(t *testing.T) {
+	httpClient := &mockHttpClient{}
+	httpClient.On("Do", mock.Anything).Return(newResponse(http.StatusTooManyRequests, `{"ok": false, "error_code": 429, `+
+		`"description": "Too Many Requests: retry after 550", "parameters": {"retry_after": 550}}`), nil)
+	service, _ := newAccountService(newExecutor(httpClient, testApiUrlFmt, testToken, nil))
+
+	closed, err := service.close(context.Background())
+
+	assert.False(t, closed)
+	var apiErr *APIError
+	assert.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, 550, apiErr.ResponseParameters.RetryAfter)
 }